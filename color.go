@@ -1,5 +1,9 @@
 package devslog
 
+import (
+	"strconv"
+)
+
 type (
 	foregroundColor   []byte
 	backgroundColor   []byte
@@ -38,6 +42,8 @@ var (
 	underlineColor commonValuesColor = []byte("\x1b[4m")
 )
 
+// Color is one of the basic terminal colors, or a 256-color or 24-bit RGB
+// value created by Color256 and RGB.
 type Color uint
 
 const (
@@ -50,8 +56,55 @@ const (
 	Magenta
 	Cyan
 	White
+	BrightBlack
+	BrightRed
+	BrightGreen
+	BrightYellow
+	BrightBlue
+	BrightMagenta
+	BrightCyan
+	BrightWhite
 )
 
+const (
+	color256Flag Color = 1 << 24
+	colorRGBFlag Color = 1 << 25
+)
+
+// Color256 returns color n from the xterm 256-color palette.
+func Color256(n uint8) Color {
+	return color256Flag | Color(n)
+}
+
+// RGB returns a 24-bit color. On terminals without truecolor support it is
+// approximated by the nearest 256 or basic color.
+func RGB(r, g, b uint8) Color {
+	return colorRGBFlag | Color(r)<<16 | Color(g)<<8 | Color(b)
+}
+
+func (c Color) valid() bool {
+	switch {
+	case c&colorRGBFlag != 0:
+		return c&^(colorRGBFlag|0xffffff) == 0
+	case c&color256Flag != 0:
+		return c&^(color256Flag|0xff) == 0
+	}
+
+	return c > 0 && int(c) < len(colors)
+}
+
+func (c Color) rgb() (r, g, b uint8) {
+	switch {
+	case c&colorRGBFlag != 0:
+		return uint8(c >> 16), uint8(c >> 8), uint8(c)
+	case c&color256Flag != 0:
+		return ansi256ToRGB(uint8(c))
+	}
+
+	rgb := basicRGB[c-1]
+	return rgb[0], rgb[1], rgb[2]
+}
+
 var colors = []color{
 	{},
 	{fgBlack, bgBlack},
@@ -62,25 +115,138 @@ var colors = []color{
 	{fgMagenta, bgMagenta},
 	{fgCyan, bgCyan},
 	{fgWhite, bgWhite},
+	{[]byte("\x1b[90m"), []byte("\x1b[100m")},
+	{[]byte("\x1b[91m"), []byte("\x1b[101m")},
+	{[]byte("\x1b[92m"), []byte("\x1b[102m")},
+	{[]byte("\x1b[93m"), []byte("\x1b[103m")},
+	{[]byte("\x1b[94m"), []byte("\x1b[104m")},
+	{[]byte("\x1b[95m"), []byte("\x1b[105m")},
+	{[]byte("\x1b[96m"), []byte("\x1b[106m")},
+	{[]byte("\x1b[97m"), []byte("\x1b[107m")},
 }
 
+// Approximate RGB values of the basic colors, in the order of the Color
+// constants, used to downgrade 256 and RGB colors.
+var basicRGB = [16][3]uint8{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+var cubeLevels = [6]uint8{0, 95, 135, 175, 215, 255}
+
 func (h *developHandler) getColor(c Color) color {
-	if int(c) < len(colors) {
-		return colors[c]
+	if c == UnknownColor {
+		return color{}
+	}
+
+	if !c.valid() {
+		return colors[White]
+	}
+
+	switch {
+	case c&colorRGBFlag != 0 && h.profile == ProfileTrueColor:
+		r, g, b := c.rgb()
+		rgb := strconv.Itoa(int(r)) + ";" + strconv.Itoa(int(g)) + ";" + strconv.Itoa(int(b))
+		return color{
+			fg: foregroundColor("\x1b[38;2;" + rgb + "m"),
+			bg: backgroundColor("\x1b[48;2;" + rgb + "m"),
+		}
+	case c&(colorRGBFlag|color256Flag) != 0 && h.profile >= ProfileANSI256:
+		n := uint8(c)
+		if c&colorRGBFlag != 0 {
+			n = rgbToANSI256(c.rgb())
+		}
+
+		return color{
+			fg: foregroundColor("\x1b[38;5;" + strconv.Itoa(int(n)) + "m"),
+			bg: backgroundColor("\x1b[48;5;" + strconv.Itoa(int(n)) + "m"),
+		}
+	case c&(colorRGBFlag|color256Flag) != 0:
+		return colors[rgbToBasic(c.rgb())]
+	}
+
+	return colors[c]
+}
+
+func ansi256ToRGB(n uint8) (r, g, b uint8) {
+	switch {
+	case n < 16:
+		rgb := basicRGB[n]
+		return rgb[0], rgb[1], rgb[2]
+	case n < 232:
+		n -= 16
+		return cubeLevels[n/36], cubeLevels[n/6%6], cubeLevels[n%6]
+	}
+
+	v := 8 + (n-232)*10
+	return v, v, v
+}
+
+func rgbToANSI256(r, g, b uint8) uint8 {
+	nearestLevel := func(v uint8) uint8 {
+		var best uint8
+		for i, l := range cubeLevels {
+			if absDiff(v, l) < absDiff(v, cubeLevels[best]) {
+				best = uint8(i)
+			}
+		}
+
+		return best
+	}
+
+	ri, gi, bi := nearestLevel(r), nearestLevel(g), nearestLevel(b)
+	cube := 16 + 36*ri + 6*gi + bi
+
+	avg := (int(r) + int(g) + int(b)) / 3
+	gray := uint8(232 + min(max(avg-3, 0)/10, 23))
+
+	gr, gg, gb := ansi256ToRGB(gray)
+	cr, cg, cb := ansi256ToRGB(cube)
+	if colorDistance(gr, gg, gb, r, g, b) < colorDistance(cr, cg, cb, r, g, b) {
+		return gray
+	}
+
+	return cube
+}
+
+func rgbToBasic(r, g, b uint8) Color {
+	best := Black
+	bestDistance := -1
+	for i, rgb := range basicRGB {
+		d := colorDistance(rgb[0], rgb[1], rgb[2], r, g, b)
+		if bestDistance < 0 || d < bestDistance {
+			best = Color(i + 1)
+			bestDistance = d
+		}
 	}
 
-	return colors[White]
+	return best
+}
+
+func colorDistance(r1, g1, b1, r2, g2, b2 uint8) int {
+	dr := int(r1) - int(r2)
+	dg := int(g1) - int(g2)
+	db := int(b1) - int(b2)
+	return dr*dr + dg*dg + db*db
+}
+
+func absDiff(a, b uint8) uint8 {
+	if a > b {
+		return a - b
+	}
+
+	return b - a
 }
 
 // Color string foreground
 func (h *developHandler) colorString(b []byte, fgColor foregroundColor) []byte {
-	if h.opts.NoColor {
+	if h.opts.NoColor || fgColor == nil {
 		return b
 	}
 
-	b = append(fgColor, b...)
-	b = append(b, resetColor...)
-	return b
+	return wrapEscape(b, fgColor)
 }
 
 // Color string fainted
@@ -89,22 +255,16 @@ func (h *developHandler) colorStringFainted(b []byte, fgColor foregroundColor) [
 		return b
 	}
 
-	b = append(fgColor, b...)
-	b = append(faintColor, b...)
-	b = append(b, resetColor...)
-	return b
+	return wrapEscape(b, faintColor, fgColor)
 }
 
 // Color string background
 func (h *developHandler) colorStringBackgorund(b []byte, fgColor foregroundColor, bgColor backgroundColor) []byte {
-	if h.opts.NoColor {
+	if h.opts.NoColor || fgColor == nil && bgColor == nil {
 		return b
 	}
 
-	b = append(fgColor, b...)
-	b = append(bgColor, b...)
-	b = append(b, resetColor...)
-	return b
+	return wrapEscape(b, bgColor, fgColor)
 }
 
 // Underline text
@@ -113,9 +273,7 @@ func (h *developHandler) underlineText(b []byte) []byte {
 		return b
 	}
 
-	b = append(underlineColor, b...)
-	b = append(b, resetColor...)
-	return b
+	return wrapEscape(b, underlineColor)
 }
 
 // Fainted text
@@ -124,7 +282,24 @@ func (h *developHandler) faintedText(b []byte) []byte {
 		return b
 	}
 
-	b = append(faintColor, b...)
-	b = append(b, resetColor...)
-	return b
+	return wrapEscape(b, faintColor)
+}
+
+// wrapEscape returns a new slice with b prefixed by the escape sequences and
+// followed by the reset sequence. The escape sequences are shared between
+// goroutines, so they must never be appended to directly.
+func wrapEscape(b []byte, prefixes ...[]byte) []byte {
+	n := len(b) + len(resetColor)
+	for _, p := range prefixes {
+		n += len(p)
+	}
+
+	w := make([]byte, 0, n)
+	for _, p := range prefixes {
+		w = append(w, p...)
+	}
+
+	w = append(w, b...)
+	w = append(w, resetColor...)
+	return w
 }
@@ -77,3 +77,47 @@ func testColorFaintedText(t *testing.T, b []byte, h *developHandler) {
 		t.Errorf("\nExpected: %s\nResult:   %s\nExpected: %[1]q\nResult:   %[2]q", expected, result)
 	}
 }
+
+func TestColorProfiles(t *testing.T) {
+	cases := []struct {
+		name    string
+		profile ColorProfile
+		color   Color
+		fg      string
+		bg      string
+	}{
+		{"bright", ProfileANSI, BrightRed, "\x1b[91m", "\x1b[101m"},
+		{"256 on 256", ProfileANSI256, Color256(208), "\x1b[38;5;208m", "\x1b[48;5;208m"},
+		{"256 on ansi", ProfileANSI, Color256(196), "\x1b[91m", "\x1b[101m"},
+		{"rgb on truecolor", ProfileTrueColor, RGB(1, 2, 3), "\x1b[38;2;1;2;3m", "\x1b[48;2;1;2;3m"},
+		{"rgb on 256", ProfileANSI256, RGB(255, 135, 0), "\x1b[38;5;208m", "\x1b[48;5;208m"},
+		{"rgb gray on 256", ProfileANSI256, RGB(128, 128, 128), "\x1b[38;5;244m", "\x1b[48;5;244m"},
+		{"rgb on ansi", ProfileANSI, RGB(0, 0, 230), "\x1b[34m", "\x1b[44m"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := NewHandler(nil, nil)
+			h.profile = tc.profile
+
+			c := h.getColor(tc.color)
+			if string(c.fg) != tc.fg || string(c.bg) != tc.bg {
+				t.Errorf("\nExpected: %q %q\nResult:   %q %q", tc.fg, tc.bg, c.fg, c.bg)
+			}
+		})
+	}
+}
+
+func TestColorValid(t *testing.T) {
+	for _, c := range []Color{Black, BrightWhite, Color256(0), Color256(255), RGB(0, 0, 0), RGB(255, 255, 255)} {
+		if !c.valid() {
+			t.Errorf("Expected color %#x to be valid", uint(c))
+		}
+	}
+
+	for _, c := range []Color{UnknownColor, Color(17), color256Flag | 0x100, colorRGBFlag | color256Flag} {
+		if c.valid() {
+			t.Errorf("Expected color %#x to be invalid", uint(c))
+		}
+	}
+}
@@ -9,7 +9,6 @@ import (
 	"io"
 	"log/slog"
 	"net/url"
-	"os"
 	"reflect"
 	"runtime"
 	"sort"
@@ -20,10 +19,17 @@ import (
 )
 
 type developHandler struct {
-	opts Options
-	goas []groupOrAttrs
-	mu   sync.Mutex
-	out  io.Writer
+	opts       Options
+	goas       []groupOrAttrs
+	mu         sync.Mutex
+	out        io.Writer
+	profile    ColorProfile
+	pal        *palette
+	legendOnce *sync.Once
+	header     []headerSegment
+	width      int
+	paths      *pathShortener
+	sources    *sourceCache
 }
 
 type Options struct {
@@ -33,18 +39,57 @@ type Options struct {
 	// Max number of printed elements in slice.
 	MaxSlicePrintSize uint
 
+	// Max nesting of structs, maps and slices, deeper values are printed as "… type", default: unlimited
+	MaxDepth uint
+
+	// Show unexported struct fields, read via reflection and printed faint
+	ShowUnexportedFields bool
+
+	// Print fields of embedded structs as fields of the parent, like encoding/json
+	FlattenEmbedded bool
+
+	// Print the embedded struct next to the name of flattened fields
+	AnnotateEmbedded bool
+
 	// If the attributes should be sorted by keys
 	SortKeys bool
 
 	// Time format for timestamp, default format is "[15:04:05]"
 	TimeFormat string
 
+	// Layout of the first line, e.g. "{time} {level} {source} {msg}", default: time, source on its own line, level and message
+	HeaderFormat string
+
+	// Style of level badges, default: devslog.LevelBadgeFull
+	LevelBadge LevelBadge
+
+	// Render structs, maps and slices on one line when the line fits into this width, 0 disables
+	AdaptiveWidth uint
+
+	// Render attributes as key=value pairs on the same line as the message
+	Compact bool
+
+	// With Compact, records at this level or above are still rendered expanded, default: all records are compact
+	ExpandLevel slog.Leveler
+
 	// Add blank line after each log
 	NewLineAfterLog bool
 
 	// Indent \n in strings
 	StringIndentation bool
 
+	// Soft-wrap long strings and error messages at the terminal width, indenting continuation lines
+	Wrap bool
+
+	// Terminal width used by Wrap, default: size of the terminal or the COLUMNS environment variable
+	Width uint
+
+	// Colors of all parts of the output, default: devslog.DefaultTheme()
+	Theme *Theme
+
+	// Named levels with their own badge text, colors and icons
+	Levels *LevelRegistry
+
 	// Set color for Debug level, default: devslog.Blue
 	DebugColor Color
 
@@ -66,8 +111,53 @@ type Options struct {
 	// Disable coloring
 	NoColor bool
 
+	// When to use colors, default: devslog.ColorAuto
+	ColorMode ColorMode
+
+	// Keys or group paths (e.g. "http.request_id") whose values are colored by a hash of the value
+	HashColorKeys []string
+
+	// Print type labels (int, bool, url, error, map[string]int, ...) instead of markers
+	Accessible bool
+
+	// Glyphs of type markers, default: devslog.DefaultMarkers()
+	Markers *Markers
+
+	// Print a legend of markers and colors before the first log
+	ShowLegend bool
+
 	// Keep same color for whole source info, helpful when you want to open the line of code from terminal, but the ANSI coloring codes are in link itself
 	SameSourceInfoColor bool
+
+	// URL template of hyperlinks on source info and stack frames, e.g. devslog.VSCodeLinkFormat, default: no links
+	SourceLinkFormat string
+
+	// How to print file paths of source info and stack frames, default: devslog.SourcePathAbsolute
+	SourcePath SourcePath
+
+	// Print the function name after the source info
+	SourceFunction bool
+
+	// Print the source code around the log call below the source info for records at this level or above, default: disabled
+	SnippetLevel slog.Leveler
+
+	// Lines of source code printed before and after the log call, default: 2
+	SnippetLines uint
+
+	// Print the stack of the goroutine for records at this level or above, default: disabled
+	StackTraceLevel slog.Leveler
+
+	// Hide frames of the standard library and the runtime in stack traces
+	HideStdlibFrames bool
+
+	// Collapse consecutive frames from the same package in stack traces
+	CollapseFrames bool
+
+	// Hide frames of wrapped errors already printed for an error earlier in the chain
+	DedupeFrames bool
+
+	// Print the type of each error in the chain with its exported fields or slog.LogValuer attributes
+	ErrorFields bool
 }
 
 type groupOrAttrs struct {
@@ -101,10 +191,14 @@ func NewHandler(out io.Writer, o *Options) *developHandler {
 			h.opts.TimeFormat = "[15:04:05]"
 		}
 
-		h.opts.DebugColor = ensureValidColor(o.DebugColor, Blue)
-		h.opts.InfoColor = ensureValidColor(o.InfoColor, Green)
-		h.opts.WarnColor = ensureValidColor(o.WarnColor, Yellow)
-		h.opts.ErrorColor = ensureValidColor(o.ErrorColor, Red)
+		if o.Theme == nil {
+			h.opts.Theme = DefaultTheme()
+		}
+
+		h.opts.DebugColor = ensureValidColor(o.DebugColor, h.opts.Theme.DebugLevel)
+		h.opts.InfoColor = ensureValidColor(o.InfoColor, h.opts.Theme.InfoLevel)
+		h.opts.WarnColor = ensureValidColor(o.WarnColor, h.opts.Theme.WarnLevel)
+		h.opts.ErrorColor = ensureValidColor(o.ErrorColor, h.opts.Theme.ErrorLevel)
 
 	} else {
 		h.opts = Options{
@@ -112,6 +206,7 @@ func NewHandler(out io.Writer, o *Options) *developHandler {
 			MaxSlicePrintSize: 50,
 			SortKeys:          false,
 			TimeFormat:        "[15:04:05]",
+			Theme:             DefaultTheme(),
 			DebugColor:        Blue,
 			InfoColor:         Green,
 			WarnColor:         Yellow,
@@ -119,29 +214,32 @@ func NewHandler(out io.Writer, o *Options) *developHandler {
 		}
 	}
 
-	if envDisablesColor() {
+	h.profile = DetectColorProfile(out, h.opts.ColorMode)
+	if h.opts.NoColor {
+		h.profile = ProfileNoColor
+	}
+
+	if h.profile == ProfileNoColor {
 		h.opts.NoColor = true
 	}
 
-	return h
-}
+	h.pal = h.newPalette(h.opts.Theme)
+	h.opts.Markers = mergeMarkers(h.opts.Markers)
+	h.legendOnce = &sync.Once{}
+	h.header = parseHeaderFormat(h.opts.HeaderFormat)
+	h.paths = newPathShortener(h.opts.SourcePath)
+	h.sources = newSourceCache()
 
-// envDisablesColor reports whether the environment signals that ANSI color
-// output should be suppressed. Follows the NO_COLOR convention
-// (https://no-color.org/) — any non-empty value disables color — and the
-// older TERM=dumb convention.
-func envDisablesColor() bool {
-	if os.Getenv("NO_COLOR") != "" {
-		return true
+	h.width = int(h.opts.Width)
+	if h.width == 0 {
+		h.width = DetectWidth(out)
 	}
-	if os.Getenv("TERM") == "dumb" {
-		return true
-	}
-	return false
+
+	return h
 }
 
 func ensureValidColor(c Color, defaultColor Color) Color {
-	if c > 0 && int(c) < len(colors) {
+	if c.valid() {
 		return c
 	}
 
@@ -170,9 +268,16 @@ func (h *developHandler) WithAttrs(as []slog.Attr) slog.Handler {
 
 func (h *developHandler) withGroupOrAttrs(goa groupOrAttrs) *developHandler {
 	h2 := &developHandler{
-		opts: h.opts,
-		goas: make([]groupOrAttrs, len(h.goas)+1),
-		out:  h.out,
+		opts:       h.opts,
+		goas:       make([]groupOrAttrs, len(h.goas)+1),
+		out:        h.out,
+		profile:    h.profile,
+		pal:        h.pal,
+		legendOnce: h.legendOnce,
+		header:     h.header,
+		width:      h.width,
+		paths:      h.paths,
+		sources:    h.sources,
 	}
 
 	copy(h2.goas, h.goas)
@@ -183,11 +288,24 @@ func (h *developHandler) withGroupOrAttrs(goa groupOrAttrs) *developHandler {
 
 func (h *developHandler) Handle(ctx context.Context, r slog.Record) error {
 	b := make([]byte, 0, 1024)
-	b = append(b, h.faintedText([]byte(r.Time.Format(h.opts.TimeFormat)))...)
-	b = append(b, ' ')
-	b = h.formatSourceInfo(b, &r)
-	b = h.levelMessage(b, &r)
-	b = h.processAttributes(b, &r)
+	if h.opts.ShowLegend {
+		h.legendOnce.Do(func() {
+			b = append(b, h.legend()...)
+		})
+	}
+
+	var snippet []byte
+	if h.header != nil {
+		b = h.formatHeader(b, &r)
+		snippet = h.sourceSnippet(&r)
+	} else {
+		b = append(b, h.colorStringFainted([]byte(r.Time.Format(h.opts.TimeFormat)), h.pal.timestamp)...)
+		b = append(b, ' ')
+		b = h.formatSourceInfo(b, &r)
+		b = h.levelMessage(b, &r)
+	}
+
+	b = h.processAttributes(b, &r, snippet)
 
 	h.mu.Lock()
 	defer h.mu.Unlock()
@@ -199,48 +317,103 @@ func (h *developHandler) Handle(ctx context.Context, r slog.Record) error {
 
 func (h *developHandler) formatSourceInfo(b []byte, r *slog.Record) []byte {
 	if h.opts.AddSource {
-		f, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
-		s := &slog.Source{
-			Function: f.Function,
-			File:     f.File,
-			Line:     f.Line,
-		}
+		b = append(b, h.sourceInfo(r)...)
+		b = append(b, '\n')
+		b = append(b, h.sourceSnippet(r)...)
+	}
 
-		if h.opts.ReplaceAttr != nil {
-			attr := h.opts.ReplaceAttr([]string{}, slog.Any(slog.SourceKey, s))
-			if attr.Key == "" {
-				b = append(b, '\n')
-				return b
-			}
-		}
+	return b
+}
 
-		b = append(b, h.colorStringFainted([]byte("@@@"), fgYellow)...)
-		b = append(b, ' ')
+// source resolves the source location of the record, or nil if it was
+// removed by ReplaceAttr.
+func (h *developHandler) source(r *slog.Record) *slog.Source {
+	f, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+	s := &slog.Source{
+		Function: f.Function,
+		File:     f.File,
+		Line:     f.Line,
+	}
 
-		if h.opts.SameSourceInfoColor {
-			b = append(b, h.underlineText(h.colorStringFainted(append(append([]byte(s.File), ':'), []byte(strconv.Itoa(s.Line))...), fgCyan))...)
-		} else {
-			b = append(b, h.underlineText(h.colorStringFainted([]byte(s.File), fgCyan))...)
-			b = append(b, h.faintedText([]byte(":"))...)
-			b = append(b, h.colorStringFainted([]byte(strconv.Itoa(s.Line)), fgRed)...)
+	if h.opts.ReplaceAttr != nil {
+		attr := h.opts.ReplaceAttr([]string{}, slog.Any(slog.SourceKey, s))
+		if attr.Key == "" {
+			return nil
 		}
+	}
 
-		b = append(b, '\n')
+	return s
+}
+
+// sourceInfo renders the source location of the record, or nil if it was
+// removed by ReplaceAttr.
+func (h *developHandler) sourceInfo(r *slog.Record) (b []byte) {
+	s := h.source(r)
+	if s == nil {
+		return nil
+	}
+
+	b = append(b, h.colorStringFainted([]byte("@@@"), h.pal.sourceMarker)...)
+	b = append(b, ' ')
+
+	file := h.paths.shorten(s.File)
+
+	var fl []byte
+	if h.opts.SameSourceInfoColor {
+		fl = h.underlineText(h.colorStringFainted(append(append([]byte(file), ':'), []byte(strconv.Itoa(s.Line))...), h.pal.sourceFile))
+	} else {
+		fl = h.underlineText(h.colorStringFainted([]byte(file), h.pal.sourceFile))
+		fl = append(fl, h.faintedText([]byte(":"))...)
+		fl = append(fl, h.colorStringFainted([]byte(strconv.Itoa(s.Line)), h.pal.sourceLine)...)
+	}
+
+	b = append(b, h.sourceLink(fl, s.File, s.Line)...)
+
+	if h.opts.SourceFunction && s.Function != "" {
+		b = append(b, ' ')
+		b = append(b, h.faintedText([]byte(shortFunction(s.Function)))...)
 	}
 
 	return b
 }
 
 func (h *developHandler) levelMessage(b []byte, r *slog.Record) []byte {
-	var ls string
+	badge, c := h.levelBadge(r)
+	b = append(b, badge...)
+	b = append(b, ' ')
+	b = append(b, h.colorString([]byte(r.Message), c.fg)...)
+	b = append(b, '\n')
+
+	return b
+}
+
+// levelBadge renders the level of the record and returns the level color
+// used for the message.
+func (h *developHandler) levelBadge(r *slog.Record) ([]byte, color) {
+	ls := r.Level.String()
+	style, registered := h.opts.Levels.Lookup(r.Level)
+	replaced := registered
 	if h.opts.ReplaceAttr != nil {
 		a := h.opts.ReplaceAttr(nil, slog.Any(slog.LevelKey, r.Level))
-		ls = a.Value.String()
+		if a.Value.String() != ls {
+			ls = a.Value.String()
+			replaced = true
+		} else if registered {
+			ls = style.Name
+		}
+
 		if a.Key != "level" {
 			r.AddAttrs(a)
 		}
-	} else {
-		ls = r.Level.String()
+	} else if registered {
+		ls = style.Name
+	}
+
+	switch h.opts.LevelBadge {
+	case LevelBadgeShort:
+		if !replaced {
+			ls = abbreviateLevel(ls)
+		}
 	}
 
 	var c color
@@ -256,22 +429,34 @@ func (h *developHandler) levelMessage(b []byte, r *slog.Record) []byte {
 		c = h.getColor(h.opts.ErrorColor)
 	}
 
-	b = append(b, h.colorStringBackgorund([]byte(" "+ls+" "), fgBlack, c.bg)...)
-	b = append(b, ' ')
-	b = append(b, h.colorString([]byte(r.Message), c.fg)...)
-	b = append(b, '\n')
+	textColor := h.pal.levelText
+	if registered {
+		if style.Color.valid() {
+			c = h.getColor(style.Color)
+		}
 
-	return b
-}
+		if style.TextColor.valid() {
+			textColor = h.getColor(style.TextColor).fg
+		}
 
-type visitKey struct {
-	ptr uintptr
-	typ reflect.Type
-}
+		if style.Icon != "" {
+			ls = style.Icon + " " + ls
+		}
+	}
+
+	if h.opts.LevelBadge == LevelBadgeFixed {
+		// DEBUG and ERROR are the widest standard level names
+		if pad := max(5, h.opts.Levels.badgeWidth()) - stringWidth(ls); pad > 0 {
+			ls += strings.Repeat(" ", pad)
+		}
+	}
 
-type visited map[visitKey]struct{}
+	return h.colorStringBackgorund([]byte(" "+ls+" "), textColor, c.bg), c
+}
 
-func (h *developHandler) processAttributes(b []byte, r *slog.Record) []byte {
+// processAttributes renders the attributes of r after the first line in b.
+// The snippet follows the first line, after the attributes in compact mode.
+func (h *developHandler) processAttributes(b []byte, r *slog.Record, snippet []byte) []byte {
 	var as attributes
 	r.Attrs(func(a slog.Attr) bool {
 		a.Value = a.Value.Resolve()
@@ -298,8 +483,19 @@ func (h *developHandler) processAttributes(b []byte, r *slog.Record) []byte {
 		}
 	}
 
-	vi := make(visited)
-	b = h.colorize(b, as, 0, []string{}, vi)
+	vi := newVisited()
+	if h.isCompact(r.Level) {
+		b = bytes.TrimSuffix(b, []byte("\n"))
+		b = h.colorizeCompact(b, as, nil, vi)
+		b = append(b, '\n')
+		b = append(b, snippet...)
+	} else {
+		b = append(b, snippet...)
+		b = h.colorize(b, as, 0, []string{}, vi)
+	}
+
+	b = h.formatStackTrace(b, r)
+
 	if h.opts.NewLineAfterLog {
 		b = append(b, '\n')
 	}
@@ -307,73 +503,99 @@ func (h *developHandler) processAttributes(b []byte, r *slog.Record) []byte {
 	return b
 }
 
-func (h *developHandler) colorize(b []byte, as attributes, l int, group []string, vi visited) []byte {
+func (h *developHandler) colorize(b []byte, as attributes, l int, group []string, vi *visited) []byte {
 	if h.opts.SortKeys {
 		sort.Sort(as)
 	}
 
-	paddingNoColor := h.padding(as, group, nil, h.colorString)
-	paddingColor := h.padding(as, group, fgMagenta, h.colorString)
+	padding := h.padding(as, group)
+	rows := make([]attrRow, 0, len(as))
+	markWidth := 1
 	for _, a := range as {
 		if h.opts.ReplaceAttr != nil {
 			a = h.opts.ReplaceAttr(group, a)
 		}
 
-		key := h.colorString([]byte(a.Key), fgMagenta)
-		val := []byte(a.Value.String())
-		valOld := val
-		vs := val
-		mark := []byte(" ")
+		a = formatAttr(a)
+
+		m := h.opts.Markers
+		key := h.colorString([]byte(a.Key), h.pal.key)
+		var val, vs []byte
+		if k := a.Value.Kind(); k != slog.KindAny && k != slog.KindGroup {
+			// Formatting composite values with fmt never ends for maps containing themselves.
+			val = []byte(a.Value.String())
+		}
+
+		mark := h.marker(" ", "", nil)
+		text := false
+
+		// Composite values are rendered once the column of the values is known
+		var render func(p int) []byte
 
 		switch a.Value.Kind() {
-		case slog.KindFloat64, slog.KindInt64, slog.KindUint64:
-			mark = h.colorString([]byte("#"), fgYellow)
-			val = h.colorString(val, fgYellow)
+		case slog.KindFloat64:
+			mark = h.marker(m.Number, "float", h.pal.number)
+			val = h.colorString(val, h.pal.number)
+		case slog.KindInt64:
+			mark = h.marker(m.Number, "int", h.pal.number)
+			val = h.colorString(val, h.pal.number)
+		case slog.KindUint64:
+			mark = h.marker(m.Number, "uint", h.pal.number)
+			val = h.colorString(val, h.pal.number)
 		case slog.KindBool:
-			mark = h.colorString([]byte("#"), fgBlue)
-			val = h.colorString(val, fgBlue)
+			mark = h.marker(m.Bool, "bool", h.pal.bool)
+			val = h.colorString(val, h.pal.bool)
 		case slog.KindString:
+			mark = h.marker(" ", "string", nil)
 			if len(val) == 0 {
-				val = h.colorStringFainted([]byte("empty"), fgWhite)
+				val = h.colorStringFainted([]byte("empty"), h.pal.muted)
 			} else if h.isURL(val) {
-				mark = h.colorString([]byte("*"), fgBlue)
-				val = h.underlineText(h.colorString(val, fgBlue))
+				mark = h.marker(m.URL, "url", h.pal.url)
+				val = h.underlineText(h.colorString(val, h.pal.url))
 			} else {
-				if h.opts.StringIndentation {
-					count := l*2 + (4 + (paddingNoColor))
-					val = []byte(strings.ReplaceAll(string(val), "\n", "\n"+strings.Repeat(" ", count)))
-				}
+				text = true
 			}
-		case slog.KindTime, slog.KindDuration:
-			mark = h.colorString([]byte("@"), fgCyan)
-			val = h.colorString(val, fgCyan)
+		case slog.KindTime:
+			mark = h.marker(m.Time, "time", h.pal.time)
+			val = h.colorString(val, h.pal.time)
+		case slog.KindDuration:
+			mark = h.marker(m.Time, "duration", h.pal.time)
+			val = h.colorString(val, h.pal.time)
 		case slog.KindAny:
 			av := a.Value.Any()
+			if st, ok := av.(*panicStack); ok {
+				mark = h.marker(m.Error, "stack", h.pal.stackFrame)
+				val = h.formatPanicStack(st, l)
+				break
+			}
+
 			if err, ok := av.(error); ok {
-				mark = h.colorString([]byte("E"), fgRed)
+				mark = h.marker(m.Error, "error", h.pal.err)
 				val = h.formatError(err, l)
 				break
 			}
 
 			if t, ok := av.(*time.Time); ok {
-				mark = h.colorString([]byte("@"), fgCyan)
-				val = h.colorString([]byte(t.String()), fgCyan)
+				mark = h.marker(m.Time, "time", h.pal.time)
+				val = h.colorString([]byte(t.String()), h.pal.time)
 				break
 			}
 
 			if d, ok := av.(*time.Duration); ok {
-				mark = h.colorString([]byte("@"), fgCyan)
-				val = h.colorString([]byte(d.String()), fgCyan)
+				mark = h.marker(m.Time, "duration", h.pal.time)
+				val = h.colorString([]byte(d.String()), h.pal.time)
 				break
 			}
 
 			if textMarshaller, ok := av.(encoding.TextMarshaler); ok {
+				mark = h.marker(" ", fmt.Sprintf("%T", av), nil)
 				val = atb(textMarshaller)
 				break
 			}
 
 			if h.opts.StringerFormatter {
 				if stringer, ok := av.(fmt.Stringer); ok {
+					mark = h.marker(" ", fmt.Sprintf("%T", av), nil)
 					val = []byte(stringer.String())
 					break
 				}
@@ -382,83 +604,121 @@ func (h *developHandler) colorize(b []byte, as attributes, l int, group []string
 			avt := reflect.TypeOf(av)
 			avv := reflect.ValueOf(av)
 			if avt == nil {
-				mark = h.colorString([]byte("!"), fgRed)
+				mark = h.marker(m.Invalid, "nil", h.pal.err)
 				val = h.nilString()
 				break
 			}
 
 			ut, uv, ptrs := h.reducePointerTypeValue(avt, avv)
-			val = bytes.Repeat(h.colorString([]byte("*"), fgRed), ptrs)
+			val = bytes.Repeat(h.colorString([]byte("*"), h.pal.pointer), ptrs)
 
 			switch ut.Kind() {
 			case reflect.Array:
-				mark = h.colorString([]byte("A"), fgGreen)
-				val = h.formatSlice(avt, avv, l, vi)
+				mark = h.marker(m.Array, avt.String(), h.pal.collectionMarker)
+				render = func(p int) []byte { return h.formatSlice(avt, avv, l, p, vi) }
 			case reflect.Slice:
-				mark = h.colorString([]byte("S"), fgGreen)
-				val = h.formatSlice(avt, avv, l, vi)
+				mark = h.marker(m.Slice, avt.String(), h.pal.collectionMarker)
+				render = func(p int) []byte { return h.formatSlice(avt, avv, l, p, vi) }
 			case reflect.Map:
-				mark = h.colorString([]byte("M"), fgGreen)
-				val = h.formatMap(avt, avv, l, vi)
+				mark = h.marker(m.Map, avt.String(), h.pal.collectionMarker)
+				render = func(p int) []byte { return h.formatMap(avt, avv, l, p, vi) }
 			case reflect.Struct:
-				mark = h.colorString([]byte("S"), fgYellow)
-				val = h.formatStruct(avt, avv, 0, vi)
+				mark = h.marker(m.Struct, avt.String(), h.pal.structMarker)
+				render = func(p int) []byte { return h.formatStruct(avt, avv, 0, p, vi) }
 			case reflect.Float32, reflect.Float64:
-				mark = h.colorString([]byte("#"), fgYellow)
+				mark = h.marker(m.Number, avt.String(), h.pal.number)
 				vs = atb(uv.Float())
-				val = append(val, h.colorString(vs, fgYellow)...)
+				val = append(val, h.colorString(vs, h.pal.number)...)
 			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-				mark = h.colorString([]byte("#"), fgYellow)
+				mark = h.marker(m.Number, avt.String(), h.pal.number)
 				vs = atb(uv.Int())
-				val = append(val, h.colorString(vs, fgYellow)...)
+				val = append(val, h.colorString(vs, h.pal.number)...)
 			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-				mark = h.colorString([]byte("#"), fgYellow)
+				mark = h.marker(m.Number, avt.String(), h.pal.number)
 				vs = atb(uv.Uint())
-				val = append(val, h.colorString(vs, fgYellow)...)
+				val = append(val, h.colorString(vs, h.pal.number)...)
 			case reflect.Bool:
-				mark = h.colorString([]byte("#"), fgBlue)
+				mark = h.marker(m.Bool, avt.String(), h.pal.bool)
 				vs = atb(uv.Bool())
-				val = append(val, h.colorString(vs, fgBlue)...)
+				val = append(val, h.colorString(vs, h.pal.bool)...)
 			case reflect.String:
+				mark = h.marker(" ", avt.String(), nil)
 				s := uv.String()
 				if len(s) == 0 {
-					val = h.colorStringFainted([]byte("empty"), fgWhite)
+					val = h.colorStringFainted([]byte("empty"), h.pal.muted)
 				} else if h.isURL([]byte(s)) {
-					val = h.underlineText(h.colorString(val, fgBlue))
+					mark = h.marker(m.URL, "url", h.pal.url)
+					val = h.underlineText(h.colorString(val, h.pal.url))
 				} else {
 					val = []byte(uv.String())
 				}
 			default:
-				mark = h.colorString([]byte("!"), fgRed)
-				val = h.colorString(atb("Unknown type"), fgRed)
+				mark = h.marker(m.Invalid, avt.String(), h.pal.err)
+				val = h.colorString(atb("Unknown type"), h.pal.err)
 			}
 		case slog.KindGroup:
-			mark = h.colorString([]byte("G"), fgGreen)
+			mark = h.marker(m.Group, "group", h.pal.collectionMarker)
 			var ga attributes
 			ga = a.Value.Group()
-			group = append(group, a.Key)
+			g := append(group[:len(group):len(group)], a.Key)
 
 			val = []byte("\n")
-			val = append(val, h.colorize(nil, ga, l+1, group, vi)...)
+			val = append(val, h.colorize(nil, ga, l+1, g, vi)...)
 		}
 
-		b = append(b, bytes.Repeat([]byte(" "), l*2)...)
-		b = append(b, mark...)
-		b = append(b, ' ')
-		b = append(b, key...)
-		b = append(b, bytes.Repeat([]byte(" "), paddingColor-len(key))...)
-		b = append(b, ':', ' ')
-		b = append(b, val...)
+		// Hash colored values are colored after wrapping, so escapes are not counted as columns
+		var hc foregroundColor
+		if hs, c, ok := h.hashColorText(group, a); ok {
+			val, hc, text, render = []byte(hs), c, true, nil
+		}
+
+		row := attrRow{
+			mark:      mark,
+			key:       key,
+			val:       val,
+			hashColor: hc,
+			render:    render,
+			text:      text,
+			group:     a.Value.Kind() == slog.KindGroup,
+		}
 
-		stringer := reflect.ValueOf(a.Value).MethodByName("String")
-		if stringer.IsValid() && !bytes.Equal(valOld, vs) {
+		if vs != nil && a.Value.String() != string(vs) {
 			s := []byte(` "`)
 			s = append(s, []byte(a.Value.String())...)
 			s = append(s, '"')
-			b = append(b, h.colorStringFainted(s, fgWhite)...)
+			row.hint = h.colorStringFainted(s, h.pal.muted)
 		}
 
-		if a.Value.Kind() != slog.KindGroup {
+		rows = append(rows, row)
+		markWidth = max(markWidth, mark.width)
+	}
+
+	for _, row := range rows {
+		val := row.val
+		if row.render != nil {
+			val = row.render(l*2 + markWidth + padding + 3)
+		}
+
+		if row.text {
+			count := l*2 + markWidth + 3 + padding
+			val = []byte(h.indentText(string(val), count, h.opts.StringIndentation))
+		}
+
+		if row.hashColor != nil {
+			val = h.colorString(val, row.hashColor)
+		}
+
+		b = append(b, bytes.Repeat([]byte(" "), l*2)...)
+		b = append(b, row.mark.b...)
+		b = append(b, bytes.Repeat([]byte(" "), markWidth-row.mark.width)...)
+		b = append(b, ' ')
+		b = append(b, row.key...)
+		b = append(b, bytes.Repeat([]byte(" "), padding-visibleWidth(row.key))...)
+		b = append(b, ':', ' ')
+		b = append(b, val...)
+		b = append(b, row.hint...)
+
+		if !row.group {
 			b = append(b, '\n')
 		}
 	}
@@ -466,21 +726,28 @@ func (h *developHandler) colorize(b []byte, as attributes, l int, group []string
 	return b
 }
 
-func (h *developHandler) padding(a attributes, g []string, color foregroundColor, colorFunction func(b []byte, fgColor foregroundColor) []byte) int {
+// attrRow is one rendered attribute, written once the widths of all markers
+// on the same level are known.
+type attrRow struct {
+	mark      mark
+	key       []byte
+	val       []byte
+	hint      []byte
+	hashColor foregroundColor
+	render    func(p int) []byte
+	text      bool
+	group     bool
+}
+
+// padding returns the display width of the widest key in a.
+func (h *developHandler) padding(a attributes, g []string) int {
 	var padding int
 	for _, attr := range a {
 		if h.opts.ReplaceAttr != nil {
 			attr = h.opts.ReplaceAttr(g, attr)
 		}
 
-		colorLength := len(attr.Key)
-		if color != nil {
-			colorLength = len(colorFunction([]byte(attr.Key), color))
-		}
-
-		if colorLength > padding {
-			padding = colorLength
-		}
+		padding = max(padding, stringWidth(attr.Key))
 	}
 
 	return padding
@@ -493,51 +760,215 @@ func (h *developHandler) isURL(u []byte) bool {
 
 func (h *developHandler) formatError(err error, l int) (b []byte) {
 	if err == nil {
-		b = append(b, h.underlineText(h.colorString(h.nilString(), fgRed))...)
+		b = append(b, h.underlineText(h.colorString(h.nilString(), h.pal.err))...)
 
 		return
 	}
 
-	for i := 0; err != nil; i++ {
+	n := 0
+	seen := map[frameKey]struct{}{}
+
+	return h.formatErrorChain(b, err, l, "", "", &n, seen)
+}
+
+// formatErrorChain renders err and the errors it wraps, numbering them with n.
+// The line of the first error is prefixed with first, the other lines with
+// rest. Errors wrapping several errors (errors.Join, fmt.Errorf with multiple
+// %w) continue with a branch for each of them.
+func (h *developHandler) formatErrorChain(b []byte, err error, l int, first, rest string, n *int, seen map[frameKey]struct{}) []byte {
+	indent := func(b []byte, prefix string) []byte {
 		b = append(b, '\n')
 		b = append(b, bytes.Repeat([]byte(" "), l*2+4)...)
-		b = append(b, h.colorString([]byte(strconv.Itoa(i)), fgRed)...)
-		b = append(b, h.colorString([]byte(": "), fgWhite)...)
+		if prefix == "" {
+			return b
+		}
 
-		errMsg := err.Error()
+		return append(b, h.colorString([]byte(prefix), h.pal.muted)...)
+	}
+
+	block := func(b []byte, lines []byte) []byte {
+		for _, line := range bytes.Split(lines, []byte("\n")) {
+			b = indent(b, rest)
+			b = append(b, ' ', ' ')
+			b = append(b, line...)
+		}
+
+		return b
+	}
+
+	prefix := first
+	for err != nil {
+		i := *n
+		*n++
+
+		var attrs []slog.Attr
+		err, attrs = unwrapAttrs(err)
+
+		b = indent(b, prefix)
+		b = append(b, h.colorString([]byte(strconv.Itoa(i)), h.pal.err)...)
+		b = append(b, h.colorString([]byte(": "), h.pal.muted)...)
+
+		children := unwrapErrors(err)
 		ue := errors.Unwrap(err)
+		errMsg := err.Error()
 		if ue != nil {
 			errMsg, _ = strings.CutSuffix(errMsg, ue.Error())
 			errMsg, _ = strings.CutSuffix(errMsg, ": ")
 		}
 
-		if errMsg == "" {
+		if len(children) > 0 {
+			errMsg = trimErrorMessages(errMsg, children)
+		}
+
+		typed := errMsg == ""
+		if typed {
 			errMsg = fmt.Sprintf("[%T]", err)
 		}
 
-		b = append(b, h.colorString([]byte(errMsg), fgRed)...)
+		errMsg = h.indentText(errMsg, l*2+6+stringWidth(prefix)+len(strconv.Itoa(i)), false)
+		b = append(b, h.colorString([]byte(errMsg), h.pal.err)...)
 
-		for j, fileLine := range h.getFileLineFromPC(h.extractPCFromError(err)) {
-			b = append(b, '\n')
+		if h.opts.ErrorFields {
+			details := h.errorDetails(err, !typed, l*2+4+stringWidth(rest)+2)
+			line, lines, ok := bytes.Cut(details, []byte("\n"))
+			b = append(b, line...)
+			if ok {
+				b = block(b, lines)
+			}
+		}
+
+		if len(attrs) > 0 {
+			b = block(b, bytes.TrimSuffix(h.colorize(nil, attrs, 1, []string{}, newVisited()), []byte("\n")))
+		}
+
+		frs, shown := h.extractFramesFromError(err), 0
+		if h.opts.DedupeFrames {
+			frs, shown = dedupeFrames(frs, seen)
+		}
+
+		for j, row := range h.frameRows(frs) {
 			tb := strconv.Itoa(j)
-			b = append(b, bytes.Repeat([]byte(" "), l*2+6)...)
-			b = append(b, bytes.Repeat([]byte(" "), len(tb))...)
-			b = append(b, h.colorString([]byte(tb), fgBlue)...)
+			b = indent(b, rest)
+			b = append(b, bytes.Repeat([]byte(" "), 2+len(tb))...)
+			b = append(b, h.colorString([]byte(tb), h.pal.stackFrame)...)
 			b = append(b, []byte(": ")...)
-			b = append(b, h.underlineText(h.colorString([]byte(fileLine), fgBlue))...)
+			b = h.formatFrame(b, row)
+		}
+
+		if shown > 0 {
+			b = indent(b, rest)
+			b = append(b, bytes.Repeat([]byte(" "), 3)...)
+			b = append(b, h.colorStringFainted([]byte("(+"+strconv.Itoa(shown)+" "+plural(shown, "frame")+" shown above)"), h.pal.muted)...)
+		}
+
+		for k, c := range children {
+			if k == len(children)-1 {
+				b = h.formatErrorChain(b, c, l, rest+"└─ ", rest+"   ", n, seen)
+			} else {
+				b = h.formatErrorChain(b, c, l, rest+"├─ ", rest+"│  ", n, seen)
+			}
 		}
 
+		prefix = rest
 		err = ue
 	}
 
 	return b
 }
 
-func (h *developHandler) formatSlice(st reflect.Type, sv reflect.Value, l int, vi visited) (b []byte) {
+// errorDetails renders the dynamic type of err unless it is already in the
+// message, followed by the attributes of its LogValue or its exported fields.
+// The lines following the type are indented relative to column p.
+func (h *developHandler) errorDetails(err error, withType bool, p int) (b []byte) {
+	if withType {
+		b = append(b, ' ')
+		b = append(b, h.buildTypeString(fmt.Sprintf("%T", err))...)
+	}
+
+	if lv, ok := err.(slog.LogValuer); ok {
+		v := slog.AnyValue(lv).Resolve()
+		as := attributes{slog.Any("value", v)}
+		if v.Kind() == slog.KindGroup {
+			as = v.Group()
+		}
+
+		if len(as) > 0 {
+			b = append(b, '\n')
+			b = append(b, bytes.TrimSuffix(h.colorize(nil, as, 1, []string{}, newVisited()), []byte("\n"))...)
+		}
+
+		return b
+	}
+
+	v := reflect.ValueOf(err)
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return b
+		}
+
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct || h.structKeyPadding(v) == 0 {
+		return b
+	}
+
+	fields := h.formatStruct(reflect.TypeOf(err), reflect.ValueOf(err), 0, p, newVisited())
+	if _, fields, ok := bytes.Cut(fields, []byte("\n")); ok {
+		b = append(b, '\n')
+		return append(b, fields...)
+	}
+
+	// Rendered on one line with AdaptiveWidth, including the type
+	if !withType {
+		_, fields, _ = bytes.Cut(fields, []byte(" "))
+	}
+
+	return append([]byte{' '}, fields...)
+}
+
+// unwrapErrors returns the errors wrapped by errors.Join or by fmt.Errorf
+// with multiple %w verbs.
+func unwrapErrors(err error) (errs []error) {
+	u, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		return nil
+	}
+
+	for _, e := range u.Unwrap() {
+		if e != nil {
+			errs = append(errs, e)
+		}
+	}
+
+	return errs
+}
+
+// trimErrorMessages removes the messages of the wrapped errors from msg,
+// leaving only the context added by the wrapping error.
+func trimErrorMessages(msg string, errs []error) string {
+	for _, e := range errs {
+		msg = strings.Replace(msg, e.Error(), "", 1)
+	}
+
+	return strings.Trim(msg, " ,:;\n")
+}
+
+func (h *developHandler) formatSlice(st reflect.Type, sv reflect.Value, l int, p int, vi *visited) (b []byte) {
+	if b, ok := h.enterComposite(st, sv, vi); !ok {
+		return b
+	}
+
+	defer h.leaveComposite(sv, vi)
+
+	if b, ok := h.inlineComposite(st, sv, p, vi); ok {
+		return b
+	}
+
 	ts := h.buildTypeString(st.String())
 	_, sv, _ = h.reducePointerTypeValue(st, sv)
 
-	b = append(b, h.colorString([]byte(strconv.Itoa(sv.Len())), fgBlue)...)
+	b = append(b, h.colorString([]byte(strconv.Itoa(sv.Len())), h.pal.length)...)
 	b = append(b, ' ')
 	b = append(b, ts...)
 	d := min(len(strconv.Itoa(int(h.opts.MaxSlicePrintSize))), len(strconv.Itoa(sv.Len())))
@@ -547,8 +978,8 @@ func (h *developHandler) formatSlice(st reflect.Type, sv reflect.Value, l int, v
 			b = append(b, '\n')
 			b = append(b, bytes.Repeat([]byte(" "), l*2+4)...)
 			b = append(b, bytes.Repeat([]byte(" "), d+2)...)
-			b = append(b, h.colorString([]byte("..."), fgBlue)...)
-			b = append(b, h.colorString([]byte("]"), fgGreen)...)
+			b = append(b, h.colorString([]byte("..."), h.pal.length)...)
+			b = append(b, h.colorString([]byte("]"), h.pal.typeBracket)...)
 			break
 		}
 
@@ -559,7 +990,7 @@ func (h *developHandler) formatSlice(st reflect.Type, sv reflect.Value, l int, v
 		b = append(b, '\n')
 		b = append(b, bytes.Repeat([]byte(" "), l*2+4)...)
 		b = append(b, bytes.Repeat([]byte(" "), d-len(tb))...)
-		b = append(b, h.colorString([]byte(tb), fgGreen)...)
+		b = append(b, h.colorString([]byte(tb), h.pal.sliceIndex)...)
 		b = append(b, ':')
 		b = append(b, ' ')
 		b = append(b, h.elementType(t, v, l, l*2+d+2, vi)...)
@@ -568,13 +999,22 @@ func (h *developHandler) formatSlice(st reflect.Type, sv reflect.Value, l int, v
 	return b
 }
 
-func (h *developHandler) formatMap(st reflect.Type, sv reflect.Value, l int, vi visited) (b []byte) {
+func (h *developHandler) formatMap(st reflect.Type, sv reflect.Value, l int, p int, vi *visited) (b []byte) {
+	if b, ok := h.enterComposite(st, sv, vi); !ok {
+		return b
+	}
+
+	defer h.leaveComposite(sv, vi)
+
+	if b, ok := h.inlineComposite(st, sv, p, vi); ok {
+		return b
+	}
+
 	ts := h.buildTypeString(st.String())
 	_, sv, _ = h.reducePointerTypeValue(st, sv)
 
-	pc := h.mapKeyPadding(sv, &fgGreen)
-	pr := h.mapKeyPadding(sv, nil)
-	b = append(b, h.colorString([]byte(strconv.Itoa(sv.Len())), fgBlue)...)
+	pr := h.mapKeyPadding(sv)
+	b = append(b, h.colorString([]byte(strconv.Itoa(sv.Len())), h.pal.length)...)
 	b = append(b, ' ')
 	b = append(b, ts...)
 	sk := h.sortMapKeys(sv)
@@ -583,11 +1023,11 @@ func (h *developHandler) formatMap(st reflect.Type, sv reflect.Value, l int, vi
 		v = h.reducePointerValue(v)
 		k = h.reducePointerValue(k)
 
-		tb := h.colorString(atb(k.Interface()), fgGreen)
+		tb := h.colorString(atb(k), h.pal.mapKey)
 		b = append(b, '\n')
 		b = append(b, bytes.Repeat([]byte(" "), l*2+4)...)
 		b = append(b, tb...)
-		b = append(b, bytes.Repeat([]byte(" "), pc-len(tb))...)
+		b = append(b, bytes.Repeat([]byte(" "), pr-visibleWidth(tb))...)
 		b = append(b, ':')
 		b = append(b, ' ')
 		b = append(b, h.elementType(v.Type(), v, l, l*2+pr+2, vi)...)
@@ -596,29 +1036,36 @@ func (h *developHandler) formatMap(st reflect.Type, sv reflect.Value, l int, vi
 	return b
 }
 
-func (h *developHandler) formatStruct(st reflect.Type, sv reflect.Value, l int, vi visited) (b []byte) {
-	b = h.buildTypeString(st.String())
+func (h *developHandler) formatStruct(st reflect.Type, sv reflect.Value, l int, p int, vi *visited) (b []byte) {
+	if b, ok := h.enterComposite(st, sv, vi); !ok {
+		return b
+	}
 
-	_, sv, _ = h.reducePointerTypeValue(st, sv)
-	pc := h.structKeyPadding(sv, &fgGreen)
-	pr := h.structKeyPadding(sv, nil)
+	defer h.leaveComposite(sv, vi)
 
-	for i := 0; i < sv.NumField(); i++ {
-		if !sv.Type().Field(i).IsExported() {
-			continue
-		}
+	if b, ok := h.inlineComposite(st, sv, p, vi); ok {
+		return b
+	}
 
-		v := sv.Field(i)
-		t := v.Type()
+	b = h.buildTypeString(st.String())
+
+	_, sv, _ = h.reducePointerTypeValue(st, sv)
+	pr := h.structKeyPadding(sv)
 
-		tb := h.colorString([]byte(sv.Type().Field(i).Name), fgGreen)
+	for _, f := range h.fieldValues(sv) {
+		tb := h.fieldName(f.structField)
 		b = append(b, '\n')
 		b = append(b, bytes.Repeat([]byte(" "), l*2+4)...)
 		b = append(b, tb...)
-		b = append(b, bytes.Repeat([]byte(" "), pc-len(tb))...)
+		b = append(b, bytes.Repeat([]byte(" "), pr-visibleWidth(tb))...)
 		b = append(b, ':')
 		b = append(b, ' ')
-		b = append(b, h.elementType(t, v, l, l*2+pr+2, vi)...)
+		if f.redact {
+			b = append(b, h.redactedString()...)
+			continue
+		}
+
+		b = append(b, h.elementType(f.v.Type(), f.v, l, l*2+pr+2, vi)...)
 	}
 
 	return b
@@ -626,12 +1073,16 @@ func (h *developHandler) formatStruct(st reflect.Type, sv reflect.Value, l int,
 
 var marshalTextInterface = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
 
-func (h *developHandler) elementType(t reflect.Type, v reflect.Value, l int, p int, vi visited) (b []byte) {
+func (h *developHandler) elementType(t reflect.Type, v reflect.Value, l int, p int, vi *visited) (b []byte) {
+	if fv, ft, ok := formatValue(v); ok {
+		return h.formattedValue(ft, fv, l, p, vi)
+	}
+
 	if t.Implements(marshalTextInterface) {
 		return atb(v)
 	}
 
-	if h.opts.StringerFormatter {
+	if h.opts.StringerFormatter && v.CanInterface() {
 		if stringer, ok := v.Interface().(fmt.Stringer); ok {
 			return []byte(stringer.String())
 		}
@@ -639,59 +1090,58 @@ func (h *developHandler) elementType(t reflect.Type, v reflect.Value, l int, p i
 
 	switch v.Kind() {
 	case reflect.Array:
-		b = h.formatSlice(t, v, l+1, vi)
+		b = h.formatSlice(t, v, l+1, p+4, vi)
 	case reflect.Slice:
-		b = h.formatSlice(t, v, l+1, vi)
+		b = h.formatSlice(t, v, l+1, p+4, vi)
 	case reflect.Map:
-		b = h.formatMap(t, v, l+1, vi)
+		b = h.formatMap(t, v, l+1, p+4, vi)
 	case reflect.Struct:
-		b = h.formatStruct(t, v, l+1, vi)
+		b = h.formatStruct(t, v, l+1, p+4, vi)
 	case reflect.Pointer:
-		key := visitKey{
-			ptr: v.Pointer(),
-			typ: v.Type(),
-		}
-
 		if v.IsNil() {
 			b = h.nilString()
-		} else if _, ok := vi[key]; ok {
-			b = atb(v)
+		} else if !vi.enter(v) {
+			b = h.cycleString(v.Type())
 		} else {
-			vi[key] = struct{}{}
 			b = h.elementType(t, v.Elem(), l, p, vi)
+			vi.leave(v)
 		}
 	case reflect.Float32, reflect.Float64:
-		b = h.colorString(atb(v.Float()), fgYellow)
+		b = h.colorString(atb(v.Float()), h.pal.number)
+		b = h.withTypeLabel(b, v.Type().String())
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		b = h.colorString(atb(v.Int()), fgYellow)
+		b = h.colorString(atb(v.Int()), h.pal.number)
+		b = h.withTypeLabel(b, v.Type().String())
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		b = h.colorString(atb(v.Uint()), fgYellow)
+		b = h.colorString(atb(v.Uint()), h.pal.number)
+		b = h.withTypeLabel(b, v.Type().String())
 	case reflect.Bool:
-		b = h.colorString(atb(v.Bool()), fgBlue)
+		b = h.colorString(atb(v.Bool()), h.pal.bool)
+		b = h.withTypeLabel(b, v.Type().String())
 	case reflect.String:
 		s := v.String()
 		if len(s) == 0 {
-			b = h.colorStringFainted([]byte("empty"), fgWhite)
+			b = h.colorStringFainted([]byte("empty"), h.pal.muted)
+			b = h.withTypeLabel(b, v.Type().String())
 		} else if h.isURL([]byte(s)) {
-			b = h.underlineText(h.colorString([]byte(s), fgBlue))
+			b = h.underlineText(h.colorString([]byte(s), h.pal.url))
+			b = h.withTypeLabel(b, "url")
 		} else {
-			if h.opts.StringIndentation {
-				b = []byte(strings.ReplaceAll(string(s), "\n", "\n"+strings.Repeat(" ", l*2+p+4)))
-			} else {
-				b = atb(s)
-			}
+			b = []byte(h.indentText(s, p+4, h.opts.StringIndentation))
+
+			b = h.withTypeLabel(b, v.Type().String())
 		}
 	case reflect.Interface:
 		if v.IsZero() {
 			b = h.nilString()
 		} else {
-			v = reflect.ValueOf(v.Interface())
+			v = v.Elem()
 			b = h.elementType(v.Type(), v, l, p, vi)
 		}
 	default:
 		b = atb("Unknown type: ")
 		b = append(b, atb(v.Kind())...)
-		b = h.colorString(b, fgRed)
+		b = h.colorString(b, h.pal.err)
 	}
 
 	return b
@@ -703,11 +1153,11 @@ func (h *developHandler) buildTypeString(ts string) (b []byte) {
 	for len(t) > 0 {
 		switch t[0] {
 		case '*':
-			b = append(b, h.colorString([]byte{t[0]}, fgRed)...)
+			b = append(b, h.colorString([]byte{t[0]}, h.pal.pointer)...)
 		case '[', ']':
-			b = append(b, h.colorString([]byte{t[0]}, fgGreen)...)
+			b = append(b, h.colorString([]byte{t[0]}, h.pal.typeBracket)...)
 		default:
-			b = append(b, h.colorString([]byte{t[0]}, fgYellow)...)
+			b = append(b, h.colorString([]byte{t[0]}, h.pal.typ)...)
 		}
 
 		t = t[1:]
@@ -721,43 +1171,26 @@ func (h *developHandler) sortMapKeys(rv reflect.Value) []reflect.Value {
 	ks = append(ks, rv.MapKeys()...)
 
 	sort.Slice(ks, func(i, j int) bool {
-		return fmt.Sprint(ks[i].Interface()) < fmt.Sprint(ks[j].Interface())
+		return fmt.Sprint(ks[i]) < fmt.Sprint(ks[j])
 	})
 
 	return ks
 }
 
-func (h *developHandler) mapKeyPadding(rv reflect.Value, fgColor *foregroundColor) (p int) {
+// mapKeyPadding returns the display width of the widest key of map rv.
+func (h *developHandler) mapKeyPadding(rv reflect.Value) (p int) {
 	for _, k := range rv.MapKeys() {
 		k = h.reducePointerValue(k)
-		c := len(atb(k.Interface()))
-		if fgColor != nil {
-			c = len(h.colorString(atb(k.Interface()), *fgColor))
-		}
-
-		if c > p {
-			p = c
-		}
+		p = max(p, visibleWidth(atb(k)))
 	}
 
 	return p
 }
 
-func (h *developHandler) structKeyPadding(sv reflect.Value, fgColor *foregroundColor) (p int) {
-	st := sv.Type()
-	for i := 0; i < sv.NumField(); i++ {
-		if !st.Field(i).IsExported() {
-			continue
-		}
-
-		c := len(st.Field(i).Name)
-		if fgColor != nil {
-			c = len(h.colorString([]byte(st.Field(i).Name), *fgColor))
-		}
-
-		if c > p {
-			p = c
-		}
+// structKeyPadding returns the display width of the widest logged field name of sv.
+func (h *developHandler) structKeyPadding(sv reflect.Value) (p int) {
+	for _, f := range h.fieldValues(sv) {
+		p = max(p, visibleWidth(h.fieldName(f.structField)))
 	}
 
 	return p
@@ -802,8 +1235,8 @@ func isNilValue(v reflect.Value) bool {
 }
 
 func (h *developHandler) nilString() []byte {
-	b := h.colorString([]byte("<"), fgRed)
-	b = append(b, h.colorString([]byte("nil"), fgYellow)...)
-	b = append(b, h.colorString([]byte(">"), fgRed)...)
+	b := h.colorString([]byte("<"), h.pal.pointer)
+	b = append(b, h.colorString([]byte("nil"), h.pal.nilValue)...)
+	b = append(b, h.colorString([]byte(">"), h.pal.pointer)...)
 	return b
 }
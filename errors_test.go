@@ -0,0 +1,143 @@
+package devslog
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"testing"
+)
+
+func TestErrorTree(t *testing.T) {
+	testErrorJoin(t)
+	testErrorMultiWrap(t)
+	testErrorTreeFrames(t)
+}
+
+func testErrorJoin(t *testing.T) {
+	w := &MockWriter{}
+	o := &Options{
+		TimeFormat: "[]",
+		NoColor:    true,
+	}
+
+	db := fmt.Errorf("query users: %w", errors.New("connection refused"))
+	cache := errors.New("cache miss")
+	err := fmt.Errorf("load: %w", errors.Join(db, cache))
+
+	logger := slog.New(NewHandler(w, o))
+	logger.Info("msg", slog.Any("e", err))
+
+	expected := []byte("[]  INFO  msg\nE e: \n    0: load\n    1: [*errors.joinError]\n    ├─ 2: query users\n    │  3: connection refused\n    └─ 4: cache miss\n")
+
+	if !bytes.Equal(w.WrittenData, expected) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, w.WrittenData)
+	}
+}
+
+func testErrorMultiWrap(t *testing.T) {
+	w := &MockWriter{}
+	o := &Options{
+		TimeFormat: "[]",
+		NoColor:    true,
+	}
+
+	inner := errors.Join(errors.New("a"), errors.New("b"))
+	err := fmt.Errorf("sync failed: %w, %w", errors.New("timeout"), inner)
+
+	logger := slog.New(NewHandler(w, o))
+	logger.Info("msg", slog.Any("e", err))
+
+	expected := []byte("[]  INFO  msg\nE e: \n    0: sync failed\n    ├─ 1: timeout\n    └─ 2: [*errors.joinError]\n       ├─ 3: a\n       └─ 4: b\n")
+
+	if !bytes.Equal(w.WrittenData, expected) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, w.WrittenData)
+	}
+}
+
+func testErrorTreeFrames(t *testing.T) {
+	w := &MockWriter{}
+	o := &Options{
+		TimeFormat:         "[]",
+		NoColor:            true,
+		MaxErrorStackTrace: 10,
+	}
+
+	err := errors.Join(
+		&wrapFramesTestError{msg: "first", frs: []runtime.Frame{{Function: "example.com/app.first", File: "/src/app.go", Line: 1}}},
+		&wrapFramesTestError{msg: "second", frs: []runtime.Frame{{Function: "example.com/app.second", File: "/src/app.go", Line: 2}}},
+	)
+
+	logger := slog.New(NewHandler(w, o))
+	logger.Info("msg", slog.Any("e", err))
+
+	expected := []byte("[]  INFO  msg\nE e: \n    0: [*errors.joinError]\n    ├─ 1: first\n    │     0: app.first /src/app.go:1\n    └─ 2: second\n          0: app.second /src/app.go:2\n")
+
+	if !bytes.Equal(w.WrittenData, expected) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, w.WrittenData)
+	}
+}
+
+type codeTestError struct {
+	Code      int
+	Op        string
+	Retryable bool
+	msg       string
+}
+
+func (e *codeTestError) Error() string { return e.msg }
+
+type valuerTestError struct {
+	user string
+}
+
+func (e valuerTestError) Error() string { return "forbidden" }
+func (e valuerTestError) LogValue() slog.Value {
+	return slog.GroupValue(slog.String("user", e.user), slog.Int("status", 403))
+}
+
+func TestErrorFields(t *testing.T) {
+	testErrorFields(t)
+	testErrorFieldsAdaptive(t)
+}
+
+func testErrorFields(t *testing.T) {
+	w := &MockWriter{}
+	o := &Options{
+		TimeFormat:  "[]",
+		NoColor:     true,
+		ErrorFields: true,
+	}
+
+	err := fmt.Errorf("request: %w", &codeTestError{Code: 503, Op: "GET", Retryable: true, msg: "unavailable"})
+	err = errors.Join(err, valuerTestError{user: "alice"})
+
+	logger := slog.New(NewHandler(w, o))
+	logger.Info("msg", slog.Any("e", err))
+
+	expected := []byte("[]  INFO  msg\nE e: \n    0: [*errors.joinError]\n    ├─ 1: request *fmt.wrapError\n    │  2: unavailable *devslog.codeTestError\n    │        Code     : 503\n    │        Op       : GET\n    │        Retryable: true\n    └─ 3: forbidden devslog.valuerTestError\n             user  : alice\n           # status: 403\n")
+
+	if !bytes.Equal(w.WrittenData, expected) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, w.WrittenData)
+	}
+}
+
+func testErrorFieldsAdaptive(t *testing.T) {
+	w := &MockWriter{}
+	o := &Options{
+		TimeFormat:    "[]",
+		NoColor:       true,
+		ErrorFields:   true,
+		AdaptiveWidth: 80,
+	}
+
+	logger := slog.New(NewHandler(w, o))
+	logger.Info("msg", slog.Any("e", &codeTestError{Code: 503, Op: "GET", msg: "unavailable"}))
+
+	expected := []byte("[]  INFO  msg\nE e: \n    0: unavailable *devslog.codeTestError {Code: 503, Op: GET, Retryable: false}\n")
+
+	if !bytes.Equal(w.WrittenData, expected) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, w.WrittenData)
+	}
+}
@@ -0,0 +1,64 @@
+package devslog
+
+import (
+	"runtime"
+	"sync"
+)
+
+// StackExtractor extracts the stack trace recorded by an error, so the
+// handler can print it with MaxErrorStackTrace. Register one for error
+// types the handler does not know with RegisterStackExtractor.
+type StackExtractor interface {
+	// ExtractStack returns the program counters recorded by err, as returned
+	// by runtime.Callers, or nil if err has no stack trace.
+	ExtractStack(err error) []uintptr
+}
+
+// StackExtractorFunc is an adapter to use ordinary functions as StackExtractor.
+type StackExtractorFunc func(err error) []uintptr
+
+// ExtractStack calls f(err).
+func (f StackExtractorFunc) ExtractStack(err error) []uintptr {
+	return f(err)
+}
+
+var (
+	stackExtractorsMu sync.RWMutex
+	stackExtractors   []StackExtractor
+)
+
+// RegisterStackExtractor adds e to the extractors consulted for every error
+// in the chain, before the built-in ones. Extractors registered later are
+// consulted first. It is safe for concurrent use.
+func RegisterStackExtractor(e StackExtractor) {
+	stackExtractorsMu.Lock()
+	defer stackExtractorsMu.Unlock()
+
+	stackExtractors = append([]StackExtractor{e}, stackExtractors...)
+}
+
+// registeredStack returns the stack of err from the first registered
+// extractor which knows it.
+func registeredStack(err error) []uintptr {
+	stackExtractorsMu.RLock()
+	defer stackExtractorsMu.RUnlock()
+
+	for _, e := range stackExtractors {
+		if pc := e.ExtractStack(err); len(pc) > 0 {
+			return pc
+		}
+	}
+
+	return nil
+}
+
+// callersError is implemented by github.com/go-errors/errors and by any error
+// storing the program counters from runtime.Callers.
+type callersError interface {
+	Callers() []uintptr
+}
+
+// framesError is implemented by errors storing resolved frames.
+type framesError interface {
+	StackTrace() []runtime.Frame
+}
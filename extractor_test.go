@@ -0,0 +1,106 @@
+package devslog
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"testing"
+)
+
+type callersTestError struct {
+	msg string
+	pcs []uintptr
+}
+
+func (e *callersTestError) Error() string      { return e.msg }
+func (e *callersTestError) Callers() []uintptr { return e.pcs }
+
+type framesTestError struct {
+	msg string
+	pcs []uintptr
+}
+
+func (e *framesTestError) Error() string { return e.msg }
+func (e *framesTestError) StackTrace() []runtime.Frame {
+	var frs []runtime.Frame
+	frames := runtime.CallersFrames(e.pcs)
+	for {
+		fr, more := frames.Next()
+		frs = append(frs, fr)
+		if !more {
+			return frs
+		}
+	}
+}
+
+// pkgTestFrame mimics github.com/pkg/errors.Frame, also used by github.com/cockroachdb/errors.
+type pkgTestFrame uintptr
+
+type pkgTestError struct {
+	msg string
+	pcs []uintptr
+}
+
+func (e *pkgTestError) Error() string { return e.msg }
+func (e *pkgTestError) StackTrace() []pkgTestFrame {
+	frs := make([]pkgTestFrame, len(e.pcs))
+	for i, pc := range e.pcs {
+		frs[i] = pkgTestFrame(pc)
+	}
+
+	return frs
+}
+
+type inHouseTestError struct {
+	msg   string
+	stack []uintptr
+}
+
+func (e *inHouseTestError) Error() string { return e.msg }
+
+func testCallers() []uintptr {
+	pcs := make([]uintptr, 8)
+	return pcs[:runtime.Callers(2, pcs)]
+}
+
+func TestStackExtractor(t *testing.T) {
+	RegisterStackExtractor(StackExtractorFunc(func(err error) []uintptr {
+		if e, ok := err.(*inHouseTestError); ok {
+			return e.stack
+		}
+
+		return nil
+	}))
+
+	_, _, line, _ := runtime.Caller(0)
+	errs := []error{
+		&callersTestError{msg: "callers", pcs: testCallers()},
+		&framesTestError{msg: "frames", pcs: testCallers()},
+		&pkgTestError{msg: "pkg", pcs: testCallers()},
+		&inHouseTestError{msg: "in-house", stack: testCallers()},
+	}
+
+	for i, err := range errs {
+		testStackExtractor(t, err, line+2+i)
+	}
+}
+
+func testStackExtractor(t *testing.T, err error, line int) {
+	w := &MockWriter{}
+	o := &Options{
+		TimeFormat:         "[]",
+		NoColor:            true,
+		SourcePath:         SourcePathRelative,
+		MaxErrorStackTrace: 1,
+	}
+
+	logger := slog.New(NewHandler(w, o))
+	logger.Info("msg", slog.Any("e", err))
+
+	expected := []byte(fmt.Sprintf("[]  INFO  msg\nE e: \n    0: %s\n       0: devslog.TestStackExtractor extractor_test.go:%d\n", err, line))
+
+	if !bytes.Equal(w.WrittenData, expected) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, w.WrittenData)
+	}
+}
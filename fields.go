@@ -0,0 +1,276 @@
+package devslog
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// structField is a field of a struct as it is logged, described by its
+// `log` and `json` tags.
+type structField struct {
+	index     []int
+	name      string
+	omitEmpty bool
+	redact    bool
+	exported  bool
+	tagged    bool   // the name comes from a tag
+	embedded  string // type of the embedded or inlined struct the field was promoted from
+}
+
+// fieldValue is a field of a struct value to be logged.
+type fieldValue struct {
+	*structField
+	v reflect.Value
+}
+
+// fieldsKey identifies the logged fields of a struct type under the
+// handler options.
+type fieldsKey struct {
+	typ        reflect.Type
+	unexported bool
+	flatten    bool
+}
+
+var structFieldCache sync.Map // map[fieldsKey][]structField
+
+// structFields returns the logged fields of struct type k.typ, parsed once
+// per type and options. Unexported fields are only included with k.unexported.
+// With k.flatten the fields of embedded structs are promoted to the parent.
+//
+// The `log` tag holds a comma separated list of a display name and options:
+//   - "-" leaves the field out,
+//   - "omitempty" leaves the field out if it is empty, like encoding/json,
+//   - "redact" hides the value,
+//   - "inline" renders the fields of a struct field as fields of the parent.
+//
+// Without a name in the `log` tag, the name from the `json` tag is used.
+// `json:"-"` leaves the field out and "omitempty" is honored unless the
+// field has a `log` tag.
+//
+// Fields promoted from embedded and inlined structs hide each other like in
+// encoding/json: the least nested field wins, then the only one named by a
+// tag, otherwise all of them are left out.
+func structFields(k fieldsKey) []structField {
+	if fs, ok := structFieldCache.Load(k); ok {
+		return fs.([]structField)
+	}
+
+	fs := dominantFields(parseStructFields(k, k.typ, nil, "", map[reflect.Type]bool{}))
+	cached, _ := structFieldCache.LoadOrStore(k, fs)
+
+	return cached.([]structField)
+}
+
+func parseStructFields(k fieldsKey, t reflect.Type, index []int, embedded string, inlining map[reflect.Type]bool) (fs []structField) {
+	inlining[t] = true
+	defer delete(inlining, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+
+		ft := sf.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+
+		// Like encoding/json, exported fields of unexported embedded structs are promoted.
+		promote := k.flatten && sf.Anonymous && ft.Kind() == reflect.Struct
+		if !sf.IsExported() && !promote && !k.unexported {
+			continue
+		}
+
+		f := structField{
+			index:    append(index[:len(index):len(index)], i),
+			name:     sf.Name,
+			exported: sf.IsExported() || promote,
+			embedded: embedded,
+		}
+
+		// Like encoding/json, tags of unexported fields are ignored.
+		inline := false
+		if tag, ok := sf.Tag.Lookup("log"); ok && f.exported {
+			if tag == "-" {
+				continue
+			}
+
+			for _, opt := range strings.Split(tag, ",") {
+				switch opt {
+				case "omitempty":
+					f.omitEmpty = true
+				case "redact":
+					f.redact = true
+				case "inline":
+					inline = true
+				case "":
+				default:
+					f.name = opt
+					f.tagged = true
+				}
+			}
+		} else if tag, ok := sf.Tag.Lookup("json"); ok && f.exported {
+			if tag == "-" {
+				continue
+			}
+
+			name, opts, _ := strings.Cut(tag, ",")
+			if name != "" {
+				f.name = name
+				f.tagged = true
+			}
+
+			for _, opt := range strings.Split(opts, ",") {
+				if opt == "omitempty" {
+					f.omitEmpty = true
+				}
+			}
+		}
+
+		if promote && !f.tagged {
+			inline = true
+		}
+
+		if inline && !f.redact && ft.Kind() == reflect.Struct && !inlining[ft] {
+			fs = append(fs, parseStructFields(k, ft, f.index, ft.String(), inlining)...)
+			continue
+		}
+
+		fs = append(fs, f)
+	}
+
+	return fs
+}
+
+// dominantFields leaves out the fields hidden by other fields of the same name.
+func dominantFields(fs []structField) []structField {
+	byName := map[string][]int{}
+	for i, f := range fs {
+		byName[f.name] = append(byName[f.name], i)
+	}
+
+	dominant := make([]structField, 0, len(fs))
+	for i, f := range fs {
+		if dominantField(fs, byName[f.name]) == i {
+			dominant = append(dominant, f)
+		}
+	}
+
+	return dominant
+}
+
+// dominantField returns the index of the field hiding the fields at indexes
+// of the same name, or -1 if none of them does.
+func dominantField(fs []structField, indexes []int) int {
+	depth := len(fs[indexes[0]].index)
+	for _, i := range indexes {
+		depth = min(depth, len(fs[i].index))
+	}
+
+	found, tagged := -1, -1
+	for _, i := range indexes {
+		if len(fs[i].index) != depth {
+			continue
+		}
+
+		if found >= 0 {
+			found = -2
+		} else if found == -1 {
+			found = i
+		}
+
+		if fs[i].tagged {
+			if tagged >= 0 {
+				return -1
+			}
+
+			tagged = i
+		}
+	}
+
+	if found >= 0 {
+		return found
+	}
+
+	return tagged
+}
+
+// fieldValues returns the fields of struct sv to be logged, without the
+// empty fields marked omitempty and the fields of inlined nil pointers.
+func (h *developHandler) fieldValues(sv reflect.Value) (fvs []fieldValue) {
+	fs := structFields(h.fieldsKey(sv.Type()))
+	for i := range fs {
+		v, ok := fieldByIndex(sv, fs[i].index)
+		if !ok || fs[i].omitEmpty && isEmptyValue(v) {
+			continue
+		}
+
+		fvs = append(fvs, fieldValue{structField: &fs[i], v: v})
+	}
+
+	return fvs
+}
+
+func (h *developHandler) fieldsKey(t reflect.Type) fieldsKey {
+	return fieldsKey{
+		typ:        t,
+		unexported: h.opts.ShowUnexportedFields,
+		flatten:    h.opts.FlattenEmbedded,
+	}
+}
+
+// fieldName renders the name of field f, faint if it is unexported, with
+// the embedded struct it was promoted from if AnnotateEmbedded is set.
+func (h *developHandler) fieldName(f *structField) (b []byte) {
+	if !f.exported {
+		b = h.colorStringFainted([]byte(f.name), h.pal.structField)
+	} else {
+		b = h.colorString([]byte(f.name), h.pal.structField)
+	}
+
+	if h.opts.AnnotateEmbedded && f.embedded != "" {
+		b = append(b, ' ')
+		b = append(b, h.colorStringFainted([]byte("("+f.embedded+")"), h.pal.muted)...)
+	}
+
+	return b
+}
+
+// fieldByIndex returns the nested field of v by index like
+// reflect.Value.FieldByIndex, reporting false for nil pointers on the way.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+
+			v = v.Elem()
+		}
+
+		v = v.Field(x)
+	}
+
+	return v, true
+}
+
+// isEmptyValue reports whether v is empty in the sense of omitempty of
+// encoding/json.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.Interface, reflect.Pointer:
+		return v.IsZero()
+	}
+
+	return false
+}
+
+// redactedString replaces the values of fields tagged `log:"redact"`.
+func (h *developHandler) redactedString() []byte {
+	return h.colorStringFainted([]byte("<redacted>"), h.pal.muted)
+}
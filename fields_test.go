@@ -0,0 +1,349 @@
+package devslog
+
+import (
+	"bytes"
+	"log/slog"
+	"reflect"
+	"testing"
+)
+
+type tagsTestAddress struct {
+	City string `json:"city"`
+	Zip  string `log:"zip,omitempty"`
+}
+
+type tagsTestUser struct {
+	ID       int              `json:"id"`
+	Name     string           `json:"name,omitempty"`
+	Email    string           `json:"email,omitempty"`
+	Password string           `log:"password,redact"`
+	Token    string           `log:"redact"`
+	Internal string           `json:"-"`
+	Skipped  string           `log:"-"`
+	Note     string           `json:"-" log:"note"`
+	Address  *tagsTestAddress `log:",inline"`
+	Tags     []string         `log:"labels,omitempty"`
+}
+
+func TestStructTags(t *testing.T) {
+	testStructTags(t)
+	testStructTagsNilInline(t)
+	testStructTagsCompact(t)
+	testStructFieldsCache(t)
+}
+
+func testStructTags(t *testing.T) {
+	w := &MockWriter{}
+	o := &Options{
+		TimeFormat: "[]",
+		NoColor:    true,
+	}
+
+	u := tagsTestUser{
+		ID:       1,
+		Email:    "john@example.com",
+		Password: "hunter2",
+		Token:    "abc",
+		Internal: "internal",
+		Skipped:  "skipped",
+		Note:     "vip",
+		Address:  &tagsTestAddress{City: "Prague"},
+	}
+
+	logger := slog.New(NewHandler(w, o))
+	logger.Info("msg", slog.Any("u", u))
+
+	expected := []byte("[]  INFO  msg\nS u: devslog.tagsTestUser\n    id      : 1\n    email   : john@example.com\n    password: <redacted>\n    Token   : <redacted>\n    note    : vip\n    city    : Prague\n")
+
+	if !bytes.Equal(w.WrittenData, expected) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, w.WrittenData)
+	}
+}
+
+func testStructTagsNilInline(t *testing.T) {
+	w := &MockWriter{}
+	o := &Options{
+		TimeFormat: "[]",
+		NoColor:    true,
+	}
+
+	logger := slog.New(NewHandler(w, o))
+	logger.Info("msg", slog.Any("u", tagsTestUser{ID: 2, Tags: []string{"a"}}))
+
+	expected := []byte("[]  INFO  msg\nS u: devslog.tagsTestUser\n    id      : 2\n    password: <redacted>\n    Token   : <redacted>\n    note    : empty\n    labels  : 1 []string\n      0: a\n")
+
+	if !bytes.Equal(w.WrittenData, expected) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, w.WrittenData)
+	}
+}
+
+func testStructTagsCompact(t *testing.T) {
+	w := &MockWriter{}
+	o := &Options{
+		TimeFormat: "[]",
+		NoColor:    true,
+		Compact:    true,
+	}
+
+	u := tagsTestUser{
+		ID:       1,
+		Password: "hunter2",
+		Address:  &tagsTestAddress{City: "Prague", Zip: "11000"},
+	}
+
+	logger := slog.New(NewHandler(w, o))
+	logger.Info("msg", slog.Any("u", u))
+
+	expected := []byte("[]  INFO  msg u={id: 1, password: <redacted>, Token: <redacted>, note: \"\", city: Prague, zip: 11000}\n")
+
+	if !bytes.Equal(w.WrittenData, expected) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, w.WrittenData)
+	}
+}
+
+func testStructFieldsCache(t *testing.T) {
+	typ := reflect.TypeOf(tagsTestUser{})
+
+	fs := structFields(fieldsKey{typ: typ})
+	if len(fs) != 9 {
+		t.Fatalf("Expected 9 fields, got %d", len(fs))
+	}
+
+	if &structFields(fieldsKey{typ: typ})[0] != &fs[0] {
+		t.Errorf("Expected the fields of %v to be cached", typ)
+	}
+
+	if f := fs[7]; f.name != "zip" || !f.omitEmpty || !reflect.DeepEqual(f.index, []int{8, 1}) {
+		t.Errorf("Unexpected inlined field: %+v", f)
+	}
+}
+
+type unexportedTestKey struct{ id int }
+
+func (k unexportedTestKey) String() string { return "key" }
+
+type unexportedTestState struct {
+	name    string
+	counts  map[unexportedTestKey]int
+	payload any
+	parent  *unexportedTestState
+	Public  int
+}
+
+func TestUnexportedFields(t *testing.T) {
+	testUnexportedFields(t)
+	testUnexportedFieldsColor(t)
+	testUnexportedFieldsCompact(t)
+	testUnexportedFieldsHidden(t)
+}
+
+func newUnexportedTestState() *unexportedTestState {
+	s := &unexportedTestState{
+		name:    "root",
+		counts:  map[unexportedTestKey]int{{id: 1}: 2},
+		payload: []int{1},
+		Public:  3,
+	}
+	s.parent = s
+
+	return s
+}
+
+func testUnexportedFields(t *testing.T) {
+	w := &MockWriter{}
+	o := &Options{
+		TimeFormat:           "[]",
+		NoColor:              true,
+		ShowUnexportedFields: true,
+		StringerFormatter:    true,
+		MaxDepth:             2,
+	}
+
+	logger := slog.New(NewHandler(w, o))
+	logger.Info("msg", slog.Any("s", newUnexportedTestState()))
+
+	expected := []byte("[]  INFO  msg\nS s: *devslog.unexportedTestState\n    name   : root\n    counts : 1 map[devslog.unexportedTestKey]int\n      {1}: 2\n    payload: 1 []int\n      0: 1\n    parent : <cycle: *devslog.unexportedTestState>\n    Public : 3\n")
+
+	if !bytes.Equal(w.WrittenData, expected) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, w.WrittenData)
+	}
+}
+
+func testUnexportedFieldsColor(t *testing.T) {
+	w := &MockWriter{}
+	o := &Options{
+		TimeFormat:           "[]",
+		ShowUnexportedFields: true,
+	}
+
+	logger := slog.New(NewHandler(w, o))
+	logger.Info("msg", slog.Any("s", struct {
+		name   string
+		Public int
+	}{"a", 1}))
+
+	expected := []byte("\x1b[2m[]\x1b[0m \x1b[42m\x1b[30m INFO \x1b[0m \x1b[32mmsg\x1b[0m\n\x1b[33mS\x1b[0m \x1b[35ms\x1b[0m: \x1b[33ms\x1b[0m\x1b[33mt\x1b[0m\x1b[33mr\x1b[0m\x1b[33mu\x1b[0m\x1b[33mc\x1b[0m\x1b[33mt\x1b[0m\x1b[33m \x1b[0m\x1b[33m{\x1b[0m\x1b[33m \x1b[0m\x1b[33mn\x1b[0m\x1b[33ma\x1b[0m\x1b[33mm\x1b[0m\x1b[33me\x1b[0m\x1b[33m \x1b[0m\x1b[33ms\x1b[0m\x1b[33mt\x1b[0m\x1b[33mr\x1b[0m\x1b[33mi\x1b[0m\x1b[33mn\x1b[0m\x1b[33mg\x1b[0m\x1b[33m;\x1b[0m\x1b[33m \x1b[0m\x1b[33mP\x1b[0m\x1b[33mu\x1b[0m\x1b[33mb\x1b[0m\x1b[33ml\x1b[0m\x1b[33mi\x1b[0m\x1b[33mc\x1b[0m\x1b[33m \x1b[0m\x1b[33mi\x1b[0m\x1b[33mn\x1b[0m\x1b[33mt\x1b[0m\x1b[33m \x1b[0m\x1b[33m}\x1b[0m\n    \x1b[2m\x1b[32mname\x1b[0m  : a\n    \x1b[32mPublic\x1b[0m: \x1b[33m1\x1b[0m\n")
+
+	if !bytes.Equal(w.WrittenData, expected) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, w.WrittenData)
+	}
+}
+
+func testUnexportedFieldsCompact(t *testing.T) {
+	w := &MockWriter{}
+	o := &Options{
+		TimeFormat:           "[]",
+		NoColor:              true,
+		Compact:              true,
+		ShowUnexportedFields: true,
+	}
+
+	logger := slog.New(NewHandler(w, o))
+	logger.Info("msg", slog.Any("s", newUnexportedTestState()))
+
+	expected := []byte("[]  INFO  msg s={name: root, counts: {{1}: 2}, payload: [1], parent: <cycle: *devslog.unexportedTestState>, Public: 3}\n")
+
+	if !bytes.Equal(w.WrittenData, expected) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, w.WrittenData)
+	}
+}
+
+func testUnexportedFieldsHidden(t *testing.T) {
+	w := &MockWriter{}
+	o := &Options{
+		TimeFormat: "[]",
+		NoColor:    true,
+	}
+
+	logger := slog.New(NewHandler(w, o))
+	logger.Info("msg", slog.Any("s", newUnexportedTestState()))
+
+	expected := []byte("[]  INFO  msg\nS s: *devslog.unexportedTestState\n    Public: 3\n")
+
+	if !bytes.Equal(w.WrittenData, expected) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, w.WrittenData)
+	}
+}
+
+type embedTestBase struct {
+	ID   int
+	Name string
+	Kind string
+}
+
+type embedTestAudit struct {
+	ID       int
+	Category string `json:"Kind"`
+	By       string
+}
+
+type embedTestMeta struct {
+	Version int
+}
+
+type embedTestTagged struct {
+	Region string
+}
+
+type embedTestOuter struct {
+	embedTestBase
+	*embedTestAudit
+	embedTestMeta
+	embedTestTagged `json:"location"`
+	Name            string
+}
+
+func TestFlattenEmbedded(t *testing.T) {
+	testFlattenEmbedded(t)
+	testFlattenEmbeddedNil(t)
+	testAnnotateEmbedded(t)
+	testEmbeddedNotFlattened(t)
+}
+
+func newEmbedTestOuter() embedTestOuter {
+	return embedTestOuter{
+		embedTestBase:   embedTestBase{ID: 1, Name: "base", Kind: "user"},
+		embedTestAudit:  &embedTestAudit{ID: 2, Category: "admin", By: "root"},
+		embedTestMeta:   embedTestMeta{Version: 3},
+		embedTestTagged: embedTestTagged{Region: "eu"},
+		Name:            "outer",
+	}
+}
+
+func testFlattenEmbedded(t *testing.T) {
+	w := &MockWriter{}
+	o := &Options{
+		TimeFormat:      "[]",
+		NoColor:         true,
+		FlattenEmbedded: true,
+	}
+
+	logger := slog.New(NewHandler(w, o))
+	logger.Info("msg", slog.Any("o", newEmbedTestOuter()))
+
+	expected := []byte("[]  INFO  msg\nS o: devslog.embedTestOuter\n    Kind    : admin\n    By      : root\n    Version : 3\n    location: devslog.embedTestTagged\n      Region: eu\n    Name    : outer\n")
+
+	if !bytes.Equal(w.WrittenData, expected) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, w.WrittenData)
+	}
+}
+
+func testFlattenEmbeddedNil(t *testing.T) {
+	w := &MockWriter{}
+	o := &Options{
+		TimeFormat:      "[]",
+		NoColor:         true,
+		Compact:         true,
+		FlattenEmbedded: true,
+	}
+
+	v := newEmbedTestOuter()
+	v.embedTestAudit = nil
+
+	logger := slog.New(NewHandler(w, o))
+	logger.Info("msg", slog.Any("o", v))
+
+	expected := []byte("[]  INFO  msg o={Version: 3, location: {Region: eu}, Name: outer}\n")
+
+	if !bytes.Equal(w.WrittenData, expected) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, w.WrittenData)
+	}
+}
+
+func testAnnotateEmbedded(t *testing.T) {
+	w := &MockWriter{}
+	o := &Options{
+		TimeFormat:       "[]",
+		NoColor:          true,
+		FlattenEmbedded:  true,
+		AnnotateEmbedded: true,
+	}
+
+	logger := slog.New(NewHandler(w, o))
+	logger.Info("msg", slog.Any("o", newEmbedTestOuter()))
+
+	expected := []byte("[]  INFO  msg\nS o: devslog.embedTestOuter\n    Kind (devslog.embedTestAudit)  : admin\n    By (devslog.embedTestAudit)    : root\n    Version (devslog.embedTestMeta): 3\n    location                       : devslog.embedTestTagged\n      Region: eu\n    Name                           : outer\n")
+
+	if !bytes.Equal(w.WrittenData, expected) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, w.WrittenData)
+	}
+}
+
+func testEmbeddedNotFlattened(t *testing.T) {
+	w := &MockWriter{}
+	o := &Options{
+		TimeFormat:           "[]",
+		NoColor:              true,
+		Compact:              true,
+		ShowUnexportedFields: true,
+	}
+
+	logger := slog.New(NewHandler(w, o))
+	logger.Info("msg", slog.Any("o", newEmbedTestOuter()))
+
+	expected := []byte("[]  INFO  msg o={embedTestBase: {ID: 1, Name: base, Kind: user}, embedTestAudit: {ID: 2, Kind: admin, By: root}, embedTestMeta: {Version: 3}, embedTestTagged: {Region: eu}, Name: outer}\n")
+
+	if !bytes.Equal(w.WrittenData, expected) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, w.WrittenData)
+	}
+}
@@ -0,0 +1,190 @@
+package devslog
+
+import (
+	"bytes"
+	"log/slog"
+	"reflect"
+	"sync"
+)
+
+type formatter struct {
+	typ    reflect.Type
+	format func(v reflect.Value) slog.Value
+}
+
+// matches reports whether f renders values of type t.
+func (f formatter) matches(t reflect.Type) bool {
+	return t == f.typ || f.typ.Kind() == reflect.Interface && t.Implements(f.typ)
+}
+
+var (
+	formattersMu sync.RWMutex
+	formatters   = map[reflect.Type]formatter{}
+	ifaceFormats []formatter
+)
+
+// RegisterFormatter sets f to render the values of type T, at the top level
+// as well as in slices, maps and struct fields. The returned value is printed
+// instead, e.g. a string for compact renderings of money or IDs, or a group
+// for a selection of fields. If T is an interface, f renders the values
+// implementing it which have no formatter of their own; interfaces
+// registered later are consulted first. Registering T again replaces its
+// formatter.
+//
+// Formatters are consulted before TextMarshaler and StringerFormatter. They
+// are not applied to unexported struct fields. It is safe for concurrent use.
+func RegisterFormatter[T any](f func(T) slog.Value) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	ft := formatter{
+		typ: t,
+		format: func(v reflect.Value) slog.Value {
+			return f(v.Interface().(T))
+		},
+	}
+
+	formattersMu.Lock()
+	defer formattersMu.Unlock()
+
+	if t.Kind() != reflect.Interface {
+		formatters[t] = ft
+		return
+	}
+
+	for i, iff := range ifaceFormats {
+		if iff.typ == t {
+			ifaceFormats = append(ifaceFormats[:i], ifaceFormats[i+1:]...)
+			break
+		}
+	}
+
+	ifaceFormats = append([]formatter{ft}, ifaceFormats...)
+}
+
+// registeredFormatter returns the formatter for values of type t, or for
+// the values t points to.
+func registeredFormatter(t reflect.Type) (formatter, bool) {
+	formattersMu.RLock()
+	defer formattersMu.RUnlock()
+
+	if len(formatters) == 0 && len(ifaceFormats) == 0 {
+		return formatter{}, false
+	}
+
+	for ; ; t = t.Elem() {
+		if f, ok := formatters[t]; ok {
+			return f, true
+		}
+
+		for _, f := range ifaceFormats {
+			if f.matches(t) {
+				return f, true
+			}
+		}
+
+		if t.Kind() != reflect.Pointer {
+			return formatter{}, false
+		}
+	}
+}
+
+// formatValue returns v rendered by its registered formatter and the type
+// of the value passed to the formatter.
+func formatValue(v reflect.Value) (slog.Value, reflect.Type, bool) {
+	for v.Kind() == reflect.Interface && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	if !v.IsValid() || !v.CanInterface() {
+		return slog.Value{}, nil, false
+	}
+
+	f, ok := registeredFormatter(v.Type())
+	if !ok {
+		return slog.Value{}, nil, false
+	}
+
+	for !f.matches(v.Type()) {
+		if v.IsNil() {
+			return slog.Value{}, nil, false
+		}
+
+		v = v.Elem()
+	}
+
+	if (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) && v.IsNil() {
+		return slog.Value{}, nil, false
+	}
+
+	return f.format(v).Resolve(), v.Type(), true
+}
+
+// formatAttr replaces the value of a by the value from its registered formatter.
+func formatAttr(a slog.Attr) slog.Attr {
+	if a.Value.Kind() != slog.KindAny {
+		return a
+	}
+
+	if fv, _, ok := formatValue(reflect.ValueOf(a.Value.Any())); ok {
+		a.Value = fv
+	}
+
+	return a
+}
+
+// formattedValue renders the value fv from the formatter of a value of type
+// t, nested at level l starting at column p like elementType.
+func (h *developHandler) formattedValue(t reflect.Type, fv slog.Value, l int, p int, vi *visited) []byte {
+	switch fv.Kind() {
+	case slog.KindString:
+		if fv.String() == "" {
+			return h.colorStringFainted([]byte("empty"), h.pal.muted)
+		}
+
+		return []byte(h.indentText(fv.String(), p+4, h.opts.StringIndentation))
+	case slog.KindGroup:
+		b := h.colorize(nil, fv.Group(), l+3, []string{}, vi)
+		return append([]byte{'\n'}, bytes.TrimSuffix(b, []byte("\n"))...)
+	case slog.KindAny:
+		v := reflect.ValueOf(fv.Any())
+		if !v.IsValid() {
+			return h.nilString()
+		}
+
+		if v.Type() == t {
+			return atb(v)
+		}
+
+		return h.elementType(v.Type(), v, l, p, vi)
+	}
+
+	return h.compactValue(nil, slog.Attr{Value: fv}, vi)
+}
+
+// inlineFormatted renders the value fv from the formatter of a value of
+// type t on a single line like inlineValue.
+func (h *developHandler) inlineFormatted(t reflect.Type, fv slog.Value, vi *visited) (b []byte) {
+	switch fv.Kind() {
+	case slog.KindGroup:
+		b = append(b, h.colorString([]byte("{"), h.pal.typeBracket)...)
+		for i, a := range fv.Group() {
+			if i > 0 {
+				b = append(b, ',', ' ')
+			}
+
+			b = append(b, h.colorString([]byte(a.Key), h.pal.structField)...)
+			b = append(b, ':', ' ')
+			b = append(b, h.inlineFormatted(nil, a.Value.Resolve(), vi)...)
+		}
+
+		return append(b, h.colorString([]byte("}"), h.pal.typeBracket)...)
+	case slog.KindAny:
+		v := reflect.ValueOf(fv.Any())
+		if v.IsValid() && v.Type() == t {
+			return []byte(quoteIfNeeded(string(atb(v))))
+		}
+
+		return h.inlineValue(v, vi)
+	}
+
+	return h.compactValue(nil, slog.Attr{Value: fv}, vi)
+}
@@ -0,0 +1,132 @@
+package devslog
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"testing"
+)
+
+type formatterTestMoney struct {
+	Cents    int64
+	Currency string
+}
+
+type formatterTestPoint struct {
+	Lat, Lng float64
+}
+
+type formatterTestOrder struct {
+	Total *formatterTestMoney
+	Items []formatterTestMoney
+	Where formatterTestPoint
+	Nil   *formatterTestMoney
+	By    map[string]formatterTestMoney
+}
+
+type formatterTestIDer interface {
+	FormatterTestID() string
+}
+
+type formatterTestUser struct {
+	ID int
+}
+
+func (u formatterTestUser) FormatterTestID() string {
+	return fmt.Sprintf("user-%d", u.ID)
+}
+
+func init() {
+	RegisterFormatter(func(m formatterTestMoney) slog.Value {
+		return slog.StringValue(fmt.Sprintf("%d.%02d %s", m.Cents/100, m.Cents%100, m.Currency))
+	})
+
+	RegisterFormatter(func(p formatterTestPoint) slog.Value {
+		return slog.GroupValue(slog.Float64("lat", p.Lat), slog.Float64("lng", p.Lng))
+	})
+
+	RegisterFormatter(func(id formatterTestIDer) slog.Value {
+		return slog.StringValue(id.FormatterTestID())
+	})
+}
+
+func TestFormatter(t *testing.T) {
+	testFormatter(t)
+	testFormatterCompact(t)
+	testFormatterInterface(t)
+}
+
+func newFormatterTestOrder() formatterTestOrder {
+	m := formatterTestMoney{Cents: 1234, Currency: "EUR"}
+
+	return formatterTestOrder{
+		Total: &m,
+		Items: []formatterTestMoney{m, {Cents: 5, Currency: "USD"}},
+		Where: formatterTestPoint{Lat: 1, Lng: 2},
+		By:    map[string]formatterTestMoney{"a": m},
+	}
+}
+
+func testFormatter(t *testing.T) {
+	w := &MockWriter{}
+	o := &Options{
+		TimeFormat: "[]",
+		NoColor:    true,
+	}
+
+	logger := slog.New(NewHandler(w, o))
+	logger.Info("msg",
+		slog.Any("m", formatterTestMoney{Cents: 1234, Currency: "EUR"}),
+		slog.Any("o", newFormatterTestOrder()),
+		slog.Any("p", &formatterTestPoint{Lat: 3, Lng: 4}),
+	)
+
+	expected := []byte("[]  INFO  msg\n  m: 12.34 EUR\nS o: devslog.formatterTestOrder\n    Total: 12.34 EUR\n    Items: 2 []devslog.formatterTestMoney\n      0: 12.34 EUR\n      1: 0.05 USD\n    Where: \n      # lat: 1\n      # lng: 2\n    Nil  : <nil>\n    By   : 1 map[string]devslog.formatterTestMoney\n      a: 12.34 EUR\nG p: \n  # lat: 3\n  # lng: 4\n")
+
+	if !bytes.Equal(w.WrittenData, expected) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, w.WrittenData)
+	}
+}
+
+func testFormatterCompact(t *testing.T) {
+	w := &MockWriter{}
+	o := &Options{
+		TimeFormat: "[]",
+		NoColor:    true,
+		Compact:    true,
+	}
+
+	logger := slog.New(NewHandler(w, o))
+	logger.Info("msg",
+		slog.Any("o", newFormatterTestOrder()),
+		slog.Any("p", formatterTestPoint{Lat: 3, Lng: 4}),
+	)
+
+	expected := []byte("[]  INFO  msg o={Total: \"12.34 EUR\", Items: [\"12.34 EUR\", \"0.05 USD\"], Where: {lat: 1, lng: 2}, Nil: <nil>, By: {a: \"12.34 EUR\"}} p.lat=3 p.lng=4\n")
+
+	if !bytes.Equal(w.WrittenData, expected) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, w.WrittenData)
+	}
+}
+
+func testFormatterInterface(t *testing.T) {
+	w := &MockWriter{}
+	o := &Options{
+		TimeFormat: "[]",
+		NoColor:    true,
+	}
+
+	var nilIDer formatterTestIDer
+
+	logger := slog.New(NewHandler(w, o))
+	logger.Info("msg",
+		slog.Any("u", formatterTestUser{ID: 7}),
+		slog.Any("s", []formatterTestIDer{formatterTestUser{ID: 8}, nilIDer}),
+	)
+
+	expected := []byte("[]  INFO  msg\n  u: user-7\nS s: 2 []devslog.formatterTestIDer\n    0: user-8\n    1: <nil>\n")
+
+	if !bytes.Equal(w.WrittenData, expected) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, w.WrittenData)
+	}
+}
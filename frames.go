@@ -0,0 +1,116 @@
+package devslog
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// frameRow is a frame to print, followed by the number of frames from the
+// same package collapsed into it with CollapseFrames.
+type frameRow struct {
+	fr        runtime.Frame
+	collapsed int
+}
+
+// frameKey identifies a call site in stack traces of different errors.
+type frameKey struct {
+	function string
+	file     string
+	line     int
+}
+
+func keyOfFrame(fr runtime.Frame) frameKey {
+	return frameKey{function: fr.Function, file: fr.File, line: fr.Line}
+}
+
+// frameRows drops the frames hidden by HideStdlibFrames and collapses the
+// consecutive frames of the same package with CollapseFrames.
+func (h *developHandler) frameRows(frs []runtime.Frame) (rows []frameRow) {
+	for _, fr := range frs {
+		if h.opts.HideStdlibFrames && isStdlibFunction(fr.Function) {
+			continue
+		}
+
+		if h.opts.CollapseFrames && len(rows) > 0 {
+			last := &rows[len(rows)-1]
+			if pkg := packagePath(fr.Function); pkg != "" && pkg == packagePath(last.fr.Function) {
+				last.collapsed++
+				continue
+			}
+		}
+
+		rows = append(rows, frameRow{fr: fr})
+	}
+
+	return rows
+}
+
+// formatFrame renders the function of the frame followed by its location.
+func (h *developHandler) formatFrame(b []byte, row frameRow) []byte {
+	fr := row.fr
+	if fr.Function != "" {
+		b = append(b, h.colorString([]byte(shortFunction(fr.Function)), h.pal.stackFrame)...)
+		b = append(b, ' ')
+	}
+
+	fileLine := h.paths.shorten(fr.File) + ":" + strconv.Itoa(fr.Line)
+	b = append(b, h.sourceLink(h.underlineText(h.colorStringFainted([]byte(fileLine), h.pal.stackFrame)), fr.File, fr.Line)...)
+
+	if row.collapsed > 0 {
+		b = append(b, ' ')
+		b = append(b, h.colorStringFainted([]byte("(+"+strconv.Itoa(row.collapsed)+" "+plural(row.collapsed, "frame")+")"), h.pal.muted)...)
+	}
+
+	return b
+}
+
+// packagePath returns the import path of the package of function fn,
+// e.g. net/http for net/http.(*conn).serve.
+func packagePath(fn string) string {
+	slash := strings.LastIndex(fn, "/") + 1
+	dot := strings.Index(fn[slash:], ".")
+	if dot < 0 {
+		return ""
+	}
+
+	return fn[:slash+dot]
+}
+
+// isStdlibFunction reports whether fn belongs to the standard library or the
+// runtime, whose import paths have no dot in the first element.
+func isStdlibFunction(fn string) bool {
+	pkg := packagePath(fn)
+	if pkg == "" || pkg == "main" {
+		return false
+	}
+
+	first, _, _ := strings.Cut(pkg, "/")
+
+	return !strings.Contains(first, ".")
+}
+
+func plural(n int, word string) string {
+	if n == 1 {
+		return word
+	}
+
+	return word + "s"
+}
+
+// dedupeFrames drops the frames already in seen and adds the others to it.
+// It returns the remaining frames and the number of dropped ones.
+func dedupeFrames(frs []runtime.Frame, seen map[frameKey]struct{}) (unseen []runtime.Frame, dropped int) {
+	for _, fr := range frs {
+		k := keyOfFrame(fr)
+		if _, ok := seen[k]; ok {
+			dropped++
+			continue
+		}
+
+		seen[k] = struct{}{}
+		unseen = append(unseen, fr)
+	}
+
+	return unseen, dropped
+}
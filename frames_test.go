@@ -0,0 +1,122 @@
+package devslog
+
+import (
+	"bytes"
+	"log/slog"
+	"runtime"
+	"testing"
+)
+
+type wrapFramesTestError struct {
+	msg string
+	frs []runtime.Frame
+	err error
+}
+
+func (e *wrapFramesTestError) Error() string {
+	if e.err == nil {
+		return e.msg
+	}
+
+	return e.msg + ": " + e.err.Error()
+}
+
+func (e *wrapFramesTestError) Unwrap() error               { return e.err }
+func (e *wrapFramesTestError) StackTrace() []runtime.Frame { return e.frs }
+
+func TestFrames(t *testing.T) {
+	testFramesDedupe(t)
+	testFramesHideStdlib(t)
+	testFramesCollapse(t)
+	testPackagePath(t)
+}
+
+func testFramesDedupe(t *testing.T) {
+	w := &MockWriter{}
+	o := &Options{
+		TimeFormat:         "[]",
+		NoColor:            true,
+		MaxErrorStackTrace: 10,
+		DedupeFrames:       true,
+	}
+
+	main := runtime.Frame{Function: "example.com/app.main", File: "/src/main.go", Line: 5}
+	err := &wrapFramesTestError{
+		msg: "outer",
+		frs: []runtime.Frame{{Function: "example.com/app.run", File: "/src/run.go", Line: 1}, main},
+		err: &wrapFramesTestError{
+			msg: "inner",
+			frs: []runtime.Frame{{Function: "example.com/lib.Load", File: "/lib/load.go", Line: 2}, main},
+		},
+	}
+
+	logger := slog.New(NewHandler(w, o))
+	logger.Info("msg", slog.Any("e", err))
+
+	expected := []byte("[]  INFO  msg\nE e: \n    0: outer\n       0: app.run /src/run.go:1\n       1: app.main /src/main.go:5\n    1: inner\n       0: lib.Load /lib/load.go:2\n       (+1 frame shown above)\n")
+
+	if !bytes.Equal(w.WrittenData, expected) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, w.WrittenData)
+	}
+}
+
+func testFramesHideStdlib(t *testing.T) {
+	h := NewHandler(nil, &Options{HideStdlibFrames: true})
+
+	rows := h.frameRows([]runtime.Frame{
+		{Function: "main.main"},
+		{Function: "net/http.HandlerFunc.ServeHTTP"},
+		{Function: "example.com/app.(*Server).handle"},
+		{Function: "runtime.goexit"},
+		{Function: "testing.tRunner"},
+	})
+
+	if len(rows) != 2 || rows[0].fr.Function != "main.main" || rows[1].fr.Function != "example.com/app.(*Server).handle" {
+		t.Errorf("frameRows with HideStdlibFrames = %v", rows)
+	}
+}
+
+func testFramesCollapse(t *testing.T) {
+	w := &MockWriter{}
+	o := &Options{
+		TimeFormat:         "[]",
+		NoColor:            true,
+		MaxErrorStackTrace: 10,
+		CollapseFrames:     true,
+	}
+
+	err := &wrapFramesTestError{
+		msg: "failed",
+		frs: []runtime.Frame{
+			{Function: "example.com/app.run", File: "/src/run.go", Line: 1},
+			{Function: "net/http.HandlerFunc.ServeHTTP", File: "/go/src/net/http/server.go", Line: 2},
+			{Function: "net/http.serverHandler.ServeHTTP", File: "/go/src/net/http/server.go", Line: 3},
+			{Function: "net/http.(*conn).serve", File: "/go/src/net/http/server.go", Line: 4},
+		},
+	}
+
+	logger := slog.New(NewHandler(w, o))
+	logger.Info("msg", slog.Any("e", err))
+
+	expected := []byte("[]  INFO  msg\nE e: \n    0: failed\n       0: app.run /src/run.go:1\n       1: http.HandlerFunc.ServeHTTP /go/src/net/http/server.go:2 (+2 frames)\n")
+
+	if !bytes.Equal(w.WrittenData, expected) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, w.WrittenData)
+	}
+}
+
+func testPackagePath(t *testing.T) {
+	cases := map[string]string{
+		"main.main":                         "main",
+		"net/http.(*conn).serve":            "net/http",
+		"github.com/golang-cz/devslog.Test": "github.com/golang-cz/devslog",
+		"example.com/app.(*T).M.func1":      "example.com/app",
+		"":                                  "",
+	}
+
+	for in, expected := range cases {
+		if out := packagePath(in); out != expected {
+			t.Errorf("packagePath(%q) = %q, want %q", in, out, expected)
+		}
+	}
+}
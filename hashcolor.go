@@ -0,0 +1,110 @@
+package devslog
+
+import (
+	"encoding"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"strings"
+)
+
+// Colors used for hash coloring on terminals with 16 colors.
+var hashColorsANSI = []Color{
+	Red, Green, Yellow, Blue, Magenta, Cyan,
+	BrightRed, BrightGreen, BrightYellow, BrightBlue, BrightMagenta, BrightCyan,
+}
+
+// Colors used for hash coloring on terminals with 256 or more colors: the
+// 6x6x6 color cube without grays and too dark colors.
+var hashColors256 = func() (cs []Color) {
+	for n := 16; n < 232; n++ {
+		r, g, b := (n-16)/36, (n-16)/6%6, (n-16)%6
+		if r == g && g == b {
+			continue
+		}
+
+		if max(r, g, b) < 3 {
+			continue
+		}
+
+		cs = append(cs, Color256(uint8(n)))
+	}
+
+	return cs
+}()
+
+// hashColorString returns the value of attribute a colored by a hash of the
+// value, if the attribute key or its group path is listed in HashColorKeys.
+func (h *developHandler) hashColorString(group []string, a slog.Attr) ([]byte, bool) {
+	s, c, ok := h.hashColorText(group, a)
+	if !ok {
+		return nil, false
+	}
+
+	return h.colorString([]byte(s), c), true
+}
+
+// hashColorText returns the plain value of attribute a and its hash color,
+// like hashColorString.
+func (h *developHandler) hashColorText(group []string, a slog.Attr) (string, foregroundColor, bool) {
+	if len(h.opts.HashColorKeys) == 0 || !h.isHashColorKey(group, a.Key) {
+		return "", nil, false
+	}
+
+	var s string
+	switch a.Value.Kind() {
+	case slog.KindString, slog.KindInt64, slog.KindUint64, slog.KindFloat64, slog.KindBool:
+		s = a.Value.String()
+	case slog.KindAny:
+		switch v := a.Value.Any().(type) {
+		case encoding.TextMarshaler:
+			b, err := v.MarshalText()
+			if err != nil {
+				return "", nil, false
+			}
+
+			s = string(b)
+		case fmt.Stringer:
+			s = v.String()
+		default:
+			return "", nil, false
+		}
+	default:
+		return "", nil, false
+	}
+
+	if s == "" {
+		return "", nil, false
+	}
+
+	return s, h.getColor(h.hashColor(s)).fg, true
+}
+
+func (h *developHandler) isHashColorKey(group []string, key string) bool {
+	path := key
+	if len(group) > 0 {
+		path = strings.Join(group, ".") + "." + key
+	}
+
+	for _, k := range h.opts.HashColorKeys {
+		if k == key || k == path {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hashColor picks a color for s using FNV-1a, so the same value gets the
+// same color in every handler and process.
+func (h *developHandler) hashColor(s string) Color {
+	cs := hashColorsANSI
+	if h.profile >= ProfileANSI256 {
+		cs = hashColors256
+	}
+
+	f := fnv.New32a()
+	f.Write([]byte(s))
+
+	return cs[f.Sum32()%uint32(len(cs))]
+}
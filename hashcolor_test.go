@@ -0,0 +1,57 @@
+package devslog
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"testing"
+)
+
+func TestHashColor(t *testing.T) {
+	testHashColorStable(t)
+	testHashColorKeys(t)
+}
+
+func testHashColorStable(t *testing.T) {
+	h1 := NewHandler(nil, nil)
+	h2 := NewHandler(nil, &Options{HashColorKeys: []string{"id"}})
+
+	for _, s := range []string{"a", "req-1", "9f0c1e2d"} {
+		if h1.hashColor(s) != h2.hashColor(s) {
+			t.Errorf("Expected the same color for %q in different handlers", s)
+		}
+	}
+
+	h1.profile = ProfileANSI256
+	if c := h1.hashColor("req-1"); c&color256Flag == 0 {
+		t.Errorf("Expected 256 color palette, got %#x", uint(c))
+	}
+}
+
+func testHashColorKeys(t *testing.T) {
+	w := &MockWriter{}
+	opts := &Options{
+		TimeFormat:    "[]",
+		HashColorKeys: []string{"request_id", "http.user"},
+	}
+
+	h := NewHandler(w, opts)
+	logger := slog.New(h)
+
+	logger.Info("msg",
+		slog.String("request_id", "abc"),
+		slog.Int("n", 1),
+		slog.Group("http", slog.String("user", "joe")),
+		slog.String("user", "joe"),
+	)
+
+	expected := fmt.Sprintf(
+		"\x1b[2m[]\x1b[0m \x1b[42m\x1b[30m INFO \x1b[0m \x1b[32mmsg\x1b[0m\n  \x1b[35mrequest_id\x1b[0m: %sabc\x1b[0m\n\x1b[33m#\x1b[0m \x1b[35mn\x1b[0m         : \x1b[33m1\x1b[0m\n\x1b[32mG\x1b[0m \x1b[35mhttp\x1b[0m      : \n    \x1b[35muser\x1b[0m: %sjoe\x1b[0m\n  \x1b[35muser\x1b[0m      : joe\n",
+		h.getColor(h.hashColor("abc")).fg,
+		h.getColor(h.hashColor("joe")).fg,
+	)
+
+	if !bytes.Equal(w.WrittenData, []byte(expected)) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, w.WrittenData)
+	}
+}
@@ -0,0 +1,142 @@
+package devslog
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+)
+
+// LevelBadge is the style of the level badge in front of the message.
+type LevelBadge uint
+
+const (
+	// LevelBadgeFull prints level names as they are: DEBUG, INFO, WARN, ERROR.
+	LevelBadgeFull LevelBadge = iota
+
+	// LevelBadgeFixed pads level names, with the icons of registered levels,
+	// to the width of the widest standard or registered level.
+	LevelBadgeFixed
+
+	// LevelBadgeShort abbreviates level names: DBG, INF, WRN, ERR.
+	LevelBadgeShort
+)
+
+var levelAbbreviations = []struct{ full, short string }{
+	{"DEBUG", "DBG"},
+	{"INFO", "INF"},
+	{"WARN", "WRN"},
+	{"ERROR", "ERR"},
+}
+
+// abbreviateLevel shortens the name of a slog.Level keeping its offset,
+// e.g. "DEBUG-4" becomes "DBG-4".
+func abbreviateLevel(ls string) string {
+	for _, a := range levelAbbreviations {
+		if rest, ok := strings.CutPrefix(ls, a.full); ok {
+			return a.short + rest
+		}
+	}
+
+	return ls
+}
+
+type headerField uint8
+
+const (
+	headerLiteral headerField = iota
+	headerTime
+	headerLevel
+	headerSource
+	headerMessage
+)
+
+var headerFields = map[string]headerField{
+	"{time}":   headerTime,
+	"{level}":  headerLevel,
+	"{source}": headerSource,
+	"{msg}":    headerMessage,
+}
+
+type headerSegment struct {
+	field   headerField
+	literal string
+}
+
+// parseHeaderFormat splits the HeaderFormat into placeholders and literal
+// text. Unknown placeholders are kept as literal text.
+func parseHeaderFormat(f string) (segs []headerSegment) {
+	if f == "" {
+		return nil
+	}
+
+	var literal strings.Builder
+	for len(f) > 0 {
+		if f[0] == '{' {
+			if end := strings.IndexByte(f, '}'); end > 0 {
+				if field, ok := headerFields[f[:end+1]]; ok {
+					if literal.Len() > 0 {
+						segs = append(segs, headerSegment{literal: literal.String()})
+						literal.Reset()
+					}
+
+					segs = append(segs, headerSegment{field: field})
+					f = f[end+1:]
+					continue
+				}
+			}
+		}
+
+		literal.WriteByte(f[0])
+		f = f[1:]
+	}
+
+	if literal.Len() > 0 {
+		segs = append(segs, headerSegment{literal: literal.String()})
+	}
+
+	return segs
+}
+
+// formatHeader renders the first line of the record using HeaderFormat.
+// Whitespace following an empty placeholder is dropped, so omitted parts
+// don't leave gaps.
+func (h *developHandler) formatHeader(b []byte, r *slog.Record) []byte {
+	badge, c := h.levelBadge(r)
+
+	var line []byte
+	skipSpace := false
+	for _, seg := range h.header {
+		var part []byte
+		switch seg.field {
+		case headerLiteral:
+			if skipSpace && strings.TrimSpace(seg.literal) == "" {
+				skipSpace = false
+				continue
+			}
+
+			part = []byte(seg.literal)
+		case headerTime:
+			part = h.colorStringFainted([]byte(r.Time.Format(h.opts.TimeFormat)), h.pal.timestamp)
+		case headerLevel:
+			part = badge
+		case headerSource:
+			if h.opts.AddSource {
+				part = h.sourceInfo(r)
+			}
+		case headerMessage:
+			if r.Message != "" {
+				part = h.colorString([]byte(r.Message), c.fg)
+			}
+		}
+
+		skipSpace = seg.field != headerLiteral && len(part) == 0
+		line = append(line, part...)
+	}
+
+	for _, l := range bytes.Split(line, []byte("\n")) {
+		b = append(b, bytes.TrimRight(l, " ")...)
+		b = append(b, '\n')
+	}
+
+	return b
+}
@@ -0,0 +1,125 @@
+package devslog
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"testing"
+)
+
+func TestHeader(t *testing.T) {
+	testParseHeaderFormat(t)
+	testHeaderFormat(t)
+	testHeaderFormatSource(t)
+	testLevelBadge(t)
+	testLevelBadgeFixedIcon(t)
+}
+
+func testParseHeaderFormat(t *testing.T) {
+	segs := parseHeaderFormat("{time} {unknown} {msg}")
+	expected := []headerSegment{
+		{field: headerTime},
+		{literal: " {unknown} "},
+		{field: headerMessage},
+	}
+
+	if fmt.Sprint(segs) != fmt.Sprint(expected) {
+		t.Errorf("\nExpected: %v\nGot:      %v", expected, segs)
+	}
+
+	if parseHeaderFormat("") != nil {
+		t.Error("Expected empty format to use the default layout")
+	}
+}
+
+func testHeaderFormat(t *testing.T) {
+	w := &MockWriter{}
+	opts := &Options{
+		TimeFormat:   "[]",
+		NoColor:      true,
+		HeaderFormat: "{level} {source} {msg} {time}",
+	}
+
+	logger := slog.New(NewHandler(w, opts))
+	logger.Info("msg", slog.Int("i", 1))
+
+	expected := []byte(" INFO  msg []\n# i: 1\n")
+
+	if !bytes.Equal(w.WrittenData, expected) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, w.WrittenData)
+	}
+}
+
+func testHeaderFormatSource(t *testing.T) {
+	w := &MockWriter{}
+	opts := &Options{
+		HandlerOptions: &slog.HandlerOptions{AddSource: true},
+		TimeFormat:     "[]",
+		NoColor:        true,
+		HeaderFormat:   "{time} {level} {msg} {source}",
+	}
+
+	logger := slog.New(NewHandler(w, opts))
+
+	_, file, line, _ := runtime.Caller(0)
+	logger.Info("msg")
+
+	expected := fmt.Sprintf("[]  INFO  msg @@@ %s:%d\n", file, line+1)
+
+	if !bytes.Equal(w.WrittenData, []byte(expected)) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, w.WrittenData)
+	}
+}
+
+func testLevelBadge(t *testing.T) {
+	cases := []struct {
+		badge    LevelBadge
+		expected string
+	}{
+		{LevelBadgeFull, "[]  DEBUG-4  a\n[]  INFO  b\n[]  WARN  c\n"},
+		{LevelBadgeFixed, "[]  DEBUG-4  a\n[]  INFO   b\n[]  WARN   c\n"},
+		{LevelBadgeShort, "[]  DBG-4  a\n[]  INF  b\n[]  WRN  c\n"},
+	}
+
+	for _, tc := range cases {
+		w := &MockWriter{}
+		opts := &Options{
+			HandlerOptions: &slog.HandlerOptions{Level: slog.Level(-8)},
+			TimeFormat:     "[]",
+			NoColor:        true,
+			LevelBadge:     tc.badge,
+		}
+
+		logger := slog.New(NewHandler(w, opts))
+		logger.Log(context.Background(), slog.Level(-8), "a")
+		logger.Info("b")
+		logger.Warn("c")
+
+		if !bytes.Equal(w.WrittenData, []byte(tc.expected)) {
+			t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", tc.expected, w.WrittenData)
+		}
+	}
+}
+
+func testLevelBadgeFixedIcon(t *testing.T) {
+	w := &MockWriter{}
+	opts := &Options{
+		HandlerOptions: &slog.HandlerOptions{Level: LevelTrace},
+		TimeFormat:     "[]",
+		NoColor:        true,
+		LevelBadge:     LevelBadgeFixed,
+		Levels:         NewLevelRegistry(LevelStyle{Level: LevelTrace, Name: "TRACE", Icon: "🔍"}),
+	}
+
+	logger := slog.New(NewHandler(w, opts))
+	logger.Log(context.Background(), LevelTrace, "a")
+	logger.Info("b")
+
+	expected := []byte("[]  🔍 TRACE  a\n[]  INFO      b\n")
+
+	if !bytes.Equal(w.WrittenData, expected) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, w.WrittenData)
+	}
+}
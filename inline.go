@@ -0,0 +1,284 @@
+package devslog
+
+import (
+	"encoding"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// isCompact reports whether records of level l are rendered on one line.
+func (h *developHandler) isCompact(l slog.Level) bool {
+	if !h.opts.Compact {
+		return false
+	}
+
+	return h.opts.ExpandLevel == nil || l < h.opts.ExpandLevel.Level()
+}
+
+// colorizeCompact renders attributes as key=value pairs on one line. Keys
+// inside groups are prefixed with the group path, e.g. "http.method".
+func (h *developHandler) colorizeCompact(b []byte, as attributes, group []string, vi *visited) []byte {
+	if h.opts.SortKeys {
+		sort.Sort(as)
+	}
+
+	for _, a := range as {
+		if h.opts.ReplaceAttr != nil && a.Value.Kind() != slog.KindGroup {
+			a = h.opts.ReplaceAttr(group, a)
+		}
+
+		a = formatAttr(a)
+
+		if a.Equal(slog.Attr{}) {
+			continue
+		}
+
+		if a.Value.Kind() == slog.KindGroup {
+			g := group
+			if a.Key != "" {
+				g = append(group[:len(group):len(group)], a.Key)
+			}
+
+			b = h.colorizeCompact(b, a.Value.Group(), g, vi)
+			continue
+		}
+
+		key := a.Key
+		if len(group) > 0 {
+			key = strings.Join(group, ".") + "." + key
+		}
+
+		b = append(b, ' ')
+		b = append(b, h.colorString([]byte(key), h.pal.key)...)
+		b = append(b, '=')
+		b = append(b, h.compactValue(group, a, vi)...)
+	}
+
+	return b
+}
+
+func (h *developHandler) compactValue(group []string, a slog.Attr, vi *visited) []byte {
+	if hv, ok := h.hashColorString(group, a); ok {
+		return hv
+	}
+
+	switch a.Value.Kind() {
+	case slog.KindFloat64, slog.KindInt64, slog.KindUint64:
+		return h.colorString([]byte(a.Value.String()), h.pal.number)
+	case slog.KindBool:
+		return h.colorString([]byte(a.Value.String()), h.pal.bool)
+	case slog.KindTime, slog.KindDuration:
+		return h.colorString([]byte(a.Value.String()), h.pal.time)
+	case slog.KindString:
+		return h.inlineString(a.Value.String())
+	}
+
+	av := a.Value.Any()
+	switch v := av.(type) {
+	case error:
+		return h.colorString([]byte(quoteIfNeeded(v.Error())), h.pal.err)
+	case *time.Time:
+		return h.colorString([]byte(v.String()), h.pal.time)
+	case *time.Duration:
+		return h.colorString([]byte(v.String()), h.pal.time)
+	case *panicStack:
+		return h.colorString([]byte("goroutine "+strconv.Itoa(v.goroutine)), h.pal.stackFrame)
+	}
+
+	return h.inlineValue(reflect.ValueOf(av), vi)
+}
+
+// inlineValue renders a value on a single line: slices as [1, 2], maps and
+// structs as {key: value}.
+func (h *developHandler) inlineValue(v reflect.Value, vi *visited) (b []byte) {
+	if !v.IsValid() {
+		return h.nilString()
+	}
+
+	t := v.Type()
+	if (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) && v.IsNil() {
+		return h.nilString()
+	}
+
+	if fv, ft, ok := formatValue(v); ok {
+		return h.inlineFormatted(ft, fv, vi)
+	}
+
+	switch v.Kind() {
+	case reflect.Array, reflect.Slice, reflect.Map, reflect.Struct:
+		if b, ok := h.enterComposite(t, v, vi); !ok {
+			return b
+		}
+
+		defer h.leaveComposite(v, vi)
+	}
+
+	return h.inlineBody(v, vi)
+}
+
+// inlineBody renders v on a single line like inlineValue, without checking
+// whether v contains itself.
+func (h *developHandler) inlineBody(v reflect.Value, vi *visited) (b []byte) {
+	t := v.Type()
+
+	if v.CanInterface() && t.Implements(marshalTextInterface) {
+		if tm, ok := v.Interface().(encoding.TextMarshaler); ok {
+			if text, err := tm.MarshalText(); err == nil {
+				return []byte(quoteIfNeeded(string(text)))
+			}
+		}
+	}
+
+	if h.opts.StringerFormatter && v.CanInterface() {
+		if stringer, ok := v.Interface().(fmt.Stringer); ok {
+			return []byte(quoteIfNeeded(stringer.String()))
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Array, reflect.Slice:
+		b = append(b, h.colorString([]byte("["), h.pal.typeBracket)...)
+		for i := 0; i < v.Len(); i++ {
+			if i > 0 {
+				b = append(b, ',', ' ')
+			}
+
+			if i == int(h.opts.MaxSlicePrintSize) {
+				b = append(b, h.colorString([]byte("..."), h.pal.length)...)
+				break
+			}
+
+			b = append(b, h.inlineValue(v.Index(i), vi)...)
+		}
+
+		b = append(b, h.colorString([]byte("]"), h.pal.typeBracket)...)
+	case reflect.Map:
+		b = append(b, h.colorString([]byte("{"), h.pal.typeBracket)...)
+		for i, k := range h.sortMapKeys(v) {
+			if i > 0 {
+				b = append(b, ',', ' ')
+			}
+
+			b = append(b, h.colorString(atb(h.reducePointerValue(k)), h.pal.mapKey)...)
+			b = append(b, ':', ' ')
+			b = append(b, h.inlineValue(v.MapIndex(k), vi)...)
+		}
+
+		b = append(b, h.colorString([]byte("}"), h.pal.typeBracket)...)
+	case reflect.Struct:
+		b = append(b, h.colorString([]byte("{"), h.pal.typeBracket)...)
+		for i, f := range h.fieldValues(v) {
+			if i > 0 {
+				b = append(b, ',', ' ')
+			}
+
+			b = append(b, h.fieldName(f.structField)...)
+			b = append(b, ':', ' ')
+			if f.redact {
+				b = append(b, h.redactedString()...)
+			} else {
+				b = append(b, h.inlineValue(f.v, vi)...)
+			}
+		}
+
+		b = append(b, h.colorString([]byte("}"), h.pal.typeBracket)...)
+	case reflect.Pointer:
+		if !vi.enter(v) {
+			b = h.cycleString(t)
+		} else {
+			b = h.inlineValue(v.Elem(), vi)
+			vi.leave(v)
+		}
+	case reflect.Interface:
+		b = h.inlineValue(v.Elem(), vi)
+	case reflect.Float32, reflect.Float64:
+		b = h.colorString(atb(v.Float()), h.pal.number)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		b = h.colorString(atb(v.Int()), h.pal.number)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		b = h.colorString(atb(v.Uint()), h.pal.number)
+	case reflect.Bool:
+		b = h.colorString(atb(v.Bool()), h.pal.bool)
+	case reflect.String:
+		b = h.inlineString(v.String())
+	default:
+		b = h.colorString(atb(v.Kind()), h.pal.err)
+	}
+
+	return b
+}
+
+func (h *developHandler) inlineString(s string) []byte {
+	switch {
+	case s == "":
+		return h.colorStringFainted([]byte(`""`), h.pal.muted)
+	case h.isURL([]byte(s)):
+		return h.underlineText(h.colorString([]byte(s), h.pal.url))
+	}
+
+	return []byte(quoteIfNeeded(s))
+}
+
+// quoteIfNeeded quotes s if it would be ambiguous in the one-line form.
+func quoteIfNeeded(s string) string {
+	if s == "" {
+		return `""`
+	}
+
+	for _, r := range s {
+		if unicode.IsSpace(r) || !unicode.IsPrint(r) || strings.ContainsRune(`"=,:[]{}`, r) {
+			return strconv.Quote(s)
+		}
+	}
+
+	return s
+}
+
+// inlineComposite renders a struct, map or slice on one line, prefixed with
+// its length and type like the multi-line form, if AdaptiveWidth is set and
+// the line starting at column p fits into it.
+func (h *developHandler) inlineComposite(t reflect.Type, v reflect.Value, p int, vi *visited) ([]byte, bool) {
+	width := int(h.opts.AdaptiveWidth)
+	if width == 0 {
+		return nil, false
+	}
+
+	_, uv, _ := h.reducePointerTypeValue(t, v)
+	if !uv.IsValid() {
+		return nil, false
+	}
+
+	var b []byte
+	switch uv.Kind() {
+	case reflect.Array, reflect.Slice, reflect.Map:
+		// Every element takes at least three columns, skip the obviously long ones.
+		if uv.Len()*3 > width {
+			return nil, false
+		}
+
+		b = append(b, h.colorString([]byte(strconv.Itoa(uv.Len())), h.pal.length)...)
+		b = append(b, ' ')
+	case reflect.Struct:
+		if len(structFields(h.fieldsKey(uv.Type())))*3 > width {
+			return nil, false
+		}
+	default:
+		return nil, false
+	}
+
+	b = append(b, h.buildTypeString(t.String())...)
+	b = append(b, ' ')
+
+	b = append(b, h.inlineBody(uv, vi)...)
+	if p+visibleWidth(b) > width {
+		return nil, false
+	}
+
+	return b, true
+}
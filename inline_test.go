@@ -0,0 +1,167 @@
+package devslog
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"testing"
+)
+
+func TestCompact(t *testing.T) {
+	testCompact(t)
+	testCompactExpandLevel(t)
+	testQuoteIfNeeded(t)
+}
+
+func testCompact(t *testing.T) {
+	w := &MockWriter{}
+	opts := &Options{
+		TimeFormat: "[]",
+		NoColor:    true,
+		Compact:    true,
+	}
+
+	type point struct {
+		X, Y int
+		name string
+	}
+
+	logger := slog.New(NewHandler(w, opts)).With("svc", "api")
+	logger.Info("msg",
+		slog.Int("i", 1),
+		slog.String("s", "hello world"),
+		slog.Any("err", errors.New("broken")),
+		slog.Any("p", &point{X: 1, Y: 2}),
+		slog.Any("m", map[string][]int{"a": {1, 2}}),
+		slog.Group("http", slog.String("method", "GET"), slog.Group("req", slog.Int("len", 5))),
+	)
+
+	expected := []byte("[]  INFO  msg i=1 s=\"hello world\" err=broken p={X: 1, Y: 2} m={a: [1, 2]} http.method=GET http.req.len=5 svc=api\n")
+
+	if !bytes.Equal(w.WrittenData, expected) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, w.WrittenData)
+	}
+}
+
+func testCompactExpandLevel(t *testing.T) {
+	w := &MockWriter{}
+	opts := &Options{
+		TimeFormat:  "[]",
+		NoColor:     true,
+		Compact:     true,
+		ExpandLevel: slog.LevelWarn,
+	}
+
+	logger := slog.New(NewHandler(w, opts))
+	logger.Info("info", slog.Int("i", 1))
+	logger.Warn("warn", slog.Int("i", 1))
+
+	expected := []byte("[]  INFO  info i=1\n[]  WARN  warn\n# i: 1\n")
+
+	if !bytes.Equal(w.WrittenData, expected) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, w.WrittenData)
+	}
+}
+
+func testQuoteIfNeeded(t *testing.T) {
+	cases := map[string]string{
+		"":        `""`,
+		"plain":   "plain",
+		"a b":     `"a b"`,
+		"k=v":     `"k=v"`,
+		"line\n":  `"line\n"`,
+		"naïve":   "naïve",
+		`"quote"`: `"\"quote\""`,
+	}
+
+	for in, expected := range cases {
+		if out := quoteIfNeeded(in); out != expected {
+			t.Errorf("quoteIfNeeded(%q) = %s, want %s", in, out, expected)
+		}
+	}
+}
+
+func TestAdaptive(t *testing.T) {
+	testAdaptive(t)
+	testAdaptiveAccessible(t)
+}
+
+func testAdaptive(t *testing.T) {
+	w := &MockWriter{}
+	opts := &Options{
+		TimeFormat:    "[]",
+		NoColor:       true,
+		AdaptiveWidth: 40,
+	}
+
+	type point struct {
+		X, Y int
+	}
+
+	type wide struct {
+		Name    string
+		Point   point
+		Numbers []int
+	}
+
+	logger := slog.New(NewHandler(w, opts))
+	logger.Info("msg",
+		slog.Any("p", point{X: 1, Y: 2}),
+		slog.Any("s", []int{1, 2, 3}),
+		slog.Any("w", wide{Name: "a long name of the struct", Point: point{3, 4}, Numbers: []int{5}}),
+	)
+
+	expected := []byte("[]  INFO  msg\nS p: devslog.point {X: 1, Y: 2}\nS s: 3 []int [1, 2, 3]\nS w: devslog.wide\n    Name   : a long name of the struct\n    Point  : devslog.point {X: 3, Y: 4}\n    Numbers: 1 []int [5]\n")
+
+	if !bytes.Equal(w.WrittenData, expected) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, w.WrittenData)
+	}
+}
+
+func testAdaptiveAccessible(t *testing.T) {
+	w := &MockWriter{}
+	opts := &Options{
+		TimeFormat:    "[]",
+		NoColor:       true,
+		Accessible:    true,
+		AdaptiveWidth: 40,
+	}
+
+	type point struct {
+		X, Y int
+	}
+
+	logger := slog.New(NewHandler(w, opts))
+	logger.Info("msg",
+		slog.Any("p", point{X: 1, Y: 2}),
+		slog.Any("s", []int{1, 2}),
+	)
+
+	expected := []byte("[]  INFO  msg\ndevslog.point p: devslog.point\n    X: 1 (int)\n    Y: 2 (int)\n[]int         s: 2 []int [1, 2]\n")
+
+	if !bytes.Equal(w.WrittenData, expected) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, w.WrittenData)
+	}
+
+	for _, line := range bytes.Split(w.WrittenData, []byte("\n")) {
+		if n := visibleWidth(line); n > 40 {
+			t.Errorf("line %q is %d columns wide, wider than AdaptiveWidth", line, n)
+		}
+	}
+}
+
+func TestVisibleWidth(t *testing.T) {
+	cases := map[string]int{
+		"":                          0,
+		"abc":                       3,
+		"\x1b[31mabc\x1b[0m":        3,
+		"\x1b[38;5;208mž\x1b[0m":    1,
+		"\x1b[2m\x1b[36mab\x1b[0m:": 3,
+	}
+
+	for in, expected := range cases {
+		if w := visibleWidth([]byte(in)); w != expected {
+			t.Errorf("visibleWidth(%q) = %d, want %d", in, w, expected)
+		}
+	}
+}
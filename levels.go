@@ -0,0 +1,166 @@
+package devslog
+
+import (
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// LevelStyle describes how a level is rendered.
+type LevelStyle struct {
+	// Level the style applies to
+	Level slog.Level
+
+	// Text of the level badge, e.g. "TRACE"
+	Name string
+
+	// Color of the badge background and the message, default: color of the nearest standard level
+	Color Color
+
+	// Color of the badge text, default: Theme.LevelText
+	TextColor Color
+
+	// Optional icon printed in the badge before the name, e.g. "🔍"
+	Icon string
+}
+
+// LevelRegistry holds named levels. It is safe for concurrent use, so levels
+// can be registered after the handler was created.
+type LevelRegistry struct {
+	mu     sync.RWMutex
+	levels []LevelStyle
+}
+
+// NewLevelRegistry returns a registry with the given levels registered.
+func NewLevelRegistry(levels ...LevelStyle) *LevelRegistry {
+	r := &LevelRegistry{}
+	for _, l := range levels {
+		r.Register(l)
+	}
+
+	return r
+}
+
+// Register adds a level, replacing a previous style of the same level.
+func (r *LevelRegistry) Register(s LevelStyle) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := sort.Search(len(r.levels), func(i int) bool { return r.levels[i].Level >= s.Level })
+	if i < len(r.levels) && r.levels[i].Level == s.Level {
+		r.levels[i] = s
+		return
+	}
+
+	r.levels = append(r.levels, LevelStyle{})
+	copy(r.levels[i+1:], r.levels[i:])
+	r.levels[i] = s
+}
+
+// Lookup returns the style registered for exactly level l.
+func (r *LevelRegistry) Lookup(l slog.Level) (LevelStyle, bool) {
+	if r == nil {
+		return LevelStyle{}, false
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i := sort.Search(len(r.levels), func(i int) bool { return r.levels[i].Level >= l })
+	if i < len(r.levels) && r.levels[i].Level == l {
+		return r.levels[i], true
+	}
+
+	return LevelStyle{}, false
+}
+
+// badgeWidth returns the number of columns of the widest badge text of the
+// registered levels, icon included.
+func (r *LevelRegistry) badgeWidth() (w int) {
+	if r == nil {
+		return 0
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, s := range r.levels {
+		text := s.Name
+		if s.Icon != "" {
+			text = s.Icon + " " + text
+		}
+
+		w = max(w, stringWidth(text))
+	}
+
+	return w
+}
+
+// Parse returns the level with the given name, case insensitive. Registered
+// names take precedence over the standard slog names like "INFO" or "WARN+2".
+func (r *LevelRegistry) Parse(name string) (slog.Level, bool) {
+	if l, ok := r.lookupName(name); ok {
+		return l, true
+	}
+
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(name)); err != nil {
+		return 0, false
+	}
+
+	return l, true
+}
+
+func (r *LevelRegistry) lookupName(name string) (slog.Level, bool) {
+	if r == nil {
+		return 0, false
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, s := range r.levels {
+		if strings.EqualFold(s.Name, name) {
+			return s.Level, true
+		}
+	}
+
+	return 0, false
+}
+
+// Leveler returns a slog.Leveler for the named level, to be used as
+// HandlerOptions.Level. The name is resolved on every call, falling back to
+// slog.LevelInfo for unknown names.
+func (r *LevelRegistry) Leveler(name string) slog.Leveler {
+	return namedLevel{r: r, name: name}
+}
+
+// ReplaceAttr renames registered levels, so other handlers such as
+// slog.JSONHandler print the same names.
+func (r *LevelRegistry) ReplaceAttr(groups []string, a slog.Attr) slog.Attr {
+	if a.Key != slog.LevelKey || len(groups) > 0 {
+		return a
+	}
+
+	if l, ok := a.Value.Any().(slog.Level); ok {
+		if s, ok := r.Lookup(l); ok {
+			a.Value = slog.StringValue(s.Name)
+		}
+	}
+
+	return a
+}
+
+type namedLevel struct {
+	r    *LevelRegistry
+	name string
+}
+
+func (n namedLevel) Level() slog.Level {
+	if l, ok := n.r.Parse(n.name); ok {
+		return l
+	}
+
+	return slog.LevelInfo
+}
@@ -0,0 +1,104 @@
+package devslog
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+)
+
+func TestLevelRegistry(t *testing.T) {
+	testLevelRegistryParse(t)
+	testLevelRegistryRender(t)
+	testLevelRegistryReplaceAttr(t)
+}
+
+func testLevelRegistryParse(t *testing.T) {
+	r := NewLevelRegistry(
+		LevelStyle{Level: LevelEmergency, Name: "FATAL"},
+		LevelStyle{Level: LevelTrace, Name: "TRACE"},
+	)
+
+	cases := []struct {
+		name  string
+		level slog.Level
+		ok    bool
+	}{
+		{"trace", LevelTrace, true},
+		{"FATAL", LevelEmergency, true},
+		{"warn+1", slog.Level(5), true},
+		{"unknown", 0, false},
+	}
+
+	for _, tc := range cases {
+		l, ok := r.Parse(tc.name)
+		if l != tc.level || ok != tc.ok {
+			t.Errorf("Parse(%q) = %v, %v, want %v, %v", tc.name, l, ok, tc.level, tc.ok)
+		}
+	}
+
+	h := NewHandler(nil, &Options{
+		HandlerOptions: &slog.HandlerOptions{Level: r.Leveler("trace")},
+		Levels:         r,
+	})
+
+	if !h.Enabled(context.Background(), LevelTrace) {
+		t.Error("Expected handler to be enabled for registered TRACE level")
+	}
+
+	if s, ok := r.Lookup(LevelTrace); !ok || s.Name != "TRACE" {
+		t.Errorf("Expected TRACE to be registered, got %v", s)
+	}
+}
+
+func testLevelRegistryRender(t *testing.T) {
+	w := &MockWriter{}
+	r := NewLevelRegistry(
+		LevelStyle{Level: LevelTrace, Name: "TRACE", Color: Cyan, TextColor: White},
+		LevelStyle{Level: LevelEmergency, Name: "FATAL", Icon: "💀"},
+	)
+
+	opts := &Options{
+		HandlerOptions: &slog.HandlerOptions{Level: LevelTrace},
+		TimeFormat:     "[]",
+		Levels:         r,
+	}
+
+	logger := slog.New(NewHandler(w, opts))
+	ctx := context.Background()
+	logger.Log(ctx, LevelTrace, "trace")
+	logger.Log(ctx, LevelEmergency, "fatal")
+	logger.Log(ctx, slog.Level(-6), "debug")
+
+	expected := []byte("\x1b[2m[]\x1b[0m \x1b[46m\x1b[37m TRACE \x1b[0m \x1b[36mtrace\x1b[0m\n\x1b[2m[]\x1b[0m \x1b[41m\x1b[30m 💀 FATAL \x1b[0m \x1b[31mfatal\x1b[0m\n\x1b[2m[]\x1b[0m \x1b[44m\x1b[30m DEBUG-2 \x1b[0m \x1b[34mdebug\x1b[0m\n")
+
+	if !bytes.Equal(w.WrittenData, expected) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, w.WrittenData)
+	}
+}
+
+func testLevelRegistryReplaceAttr(t *testing.T) {
+	w := &MockWriter{}
+	r := NewLevelRegistry(LevelStyle{Level: LevelEmergency, Name: "FATAL"})
+
+	opts := &Options{
+		HandlerOptions: &slog.HandlerOptions{ReplaceAttr: replaceAttributes},
+		TimeFormat:     "[]",
+		NoColor:        true,
+		Levels:         r,
+	}
+
+	logger := slog.New(NewHandler(w, opts))
+	logger.Log(context.Background(), LevelEmergency, "fatal")
+
+	expected := []byte("[]  EMERGENCY  fatal\n  sev: EMERGENCY\n")
+
+	if !bytes.Equal(w.WrittenData, expected) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, w.WrittenData)
+	}
+
+	a := r.ReplaceAttr(nil, slog.Any(slog.LevelKey, LevelEmergency))
+	if a.Value.String() != "FATAL" {
+		t.Errorf("Expected ReplaceAttr to rename level, got %v", a.Value)
+	}
+}
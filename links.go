@@ -0,0 +1,38 @@
+package devslog
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// URL templates for Options.SourceLinkFormat. {path} is replaced with the
+// absolute path of the file and {line} with the line number.
+const (
+	FileLinkFormat   = "file://{path}"
+	VSCodeLinkFormat = "vscode://file{path}:{line}"
+	IDEALinkFormat   = "idea://open?file={path}&line={line}"
+)
+
+// sourceLink wraps text into an OSC 8 hyperlink to the line of the file, so
+// the terminal opens it on click. Links are emitted only with colors, as
+// they are escape sequences too.
+func (h *developHandler) sourceLink(text []byte, file string, line int) []byte {
+	if h.opts.SourceLinkFormat == "" || h.opts.NoColor || file == "" {
+		return text
+	}
+
+	u := strings.NewReplacer(
+		"{path}", (&url.URL{Path: file}).EscapedPath(),
+		"{line}", strconv.Itoa(line),
+	).Replace(h.opts.SourceLinkFormat)
+
+	b := make([]byte, 0, len(text)+len(u)+14)
+	b = append(b, "\x1b]8;;"...)
+	b = append(b, u...)
+	b = append(b, "\x1b\\"...)
+	b = append(b, text...)
+	b = append(b, "\x1b]8;;\x1b\\"...)
+
+	return b
+}
@@ -0,0 +1,83 @@
+package devslog
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"testing"
+)
+
+func TestSourceLink(t *testing.T) {
+	testSourceLink(t)
+	testSourceLinkNoColor(t)
+	testSourceLinkEscape(t)
+}
+
+func testSourceLink(t *testing.T) {
+	w := &MockWriter{}
+	o := &Options{
+		HandlerOptions:      &slog.HandlerOptions{AddSource: true},
+		TimeFormat:          "[]",
+		SameSourceInfoColor: true,
+		SourceLinkFormat:    VSCodeLinkFormat,
+	}
+
+	logger := slog.New(NewHandler(w, o))
+
+	// Capture the line number right before the log call
+	_, file, line, _ := runtime.Caller(0)
+	logger.Info("msg")
+
+	line++
+
+	expected := fmt.Sprintf(
+		"\x1b[2m[]\x1b[0m \x1b[2m\x1b[33m@@@\x1b[0m \x1b]8;;vscode://file%[1]s:%[2]d\x1b\\\x1b[4m\x1b[2m\x1b[36m%[1]s:%[2]d\x1b[0m\x1b[0m\x1b]8;;\x1b\\\n\x1b[42m\x1b[30m INFO \x1b[0m \x1b[32mmsg\x1b[0m\n", file, line,
+	)
+
+	if !bytes.Equal(w.WrittenData, []byte(expected)) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, w.WrittenData)
+	}
+}
+
+func testSourceLinkNoColor(t *testing.T) {
+	w := &MockWriter{}
+	o := &Options{
+		HandlerOptions:   &slog.HandlerOptions{AddSource: true},
+		TimeFormat:       "[]",
+		NoColor:          true,
+		SourceLinkFormat: VSCodeLinkFormat,
+	}
+
+	logger := slog.New(NewHandler(w, o))
+
+	// Capture the line number right before the log call
+	_, file, line, _ := runtime.Caller(0)
+	logger.Info("msg")
+
+	line++
+
+	expected := fmt.Sprintf("[] @@@ %s:%d\n INFO  msg\n", file, line)
+
+	if !bytes.Equal(w.WrittenData, []byte(expected)) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, w.WrittenData)
+	}
+}
+
+func testSourceLinkEscape(t *testing.T) {
+	h := NewHandler(nil, &Options{
+		ColorMode:        ColorAlways,
+		SourceLinkFormat: IDEALinkFormat,
+	})
+
+	got := h.sourceLink([]byte("x"), "/my project/main.go", 7)
+	expected := []byte("\x1b]8;;idea://open?file=/my%20project/main.go&line=7\x1b\\x\x1b]8;;\x1b\\")
+
+	if !bytes.Equal(got, expected) {
+		t.Errorf("\nExpected:\n%q\nGot:\n%q", expected, got)
+	}
+
+	if w := visibleWidth(got); w != 1 {
+		t.Errorf("visibleWidth of a link = %d, want 1", w)
+	}
+}
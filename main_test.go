@@ -9,7 +9,9 @@ import (
 // ANSI-escape assertions are hermetic regardless of the developer's shell.
 // Per-test cases that exercise env-driven color disabling use t.Setenv.
 func TestMain(m *testing.M) {
-	os.Unsetenv("NO_COLOR")
-	os.Unsetenv("TERM")
+	for _, k := range []string{"NO_COLOR", "TERM", "FORCE_COLOR", "CLICOLOR", "CLICOLOR_FORCE", "COLORTERM", "COLUMNS"} {
+		os.Unsetenv(k)
+	}
+
 	os.Exit(m.Run())
 }
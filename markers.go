@@ -0,0 +1,141 @@
+package devslog
+
+import (
+	"bytes"
+)
+
+// Markers are the glyphs printed in front of attribute keys to show the
+// type of the value. Empty fields fall back to DefaultMarkers.
+type Markers struct {
+	Number  string
+	Bool    string
+	URL     string
+	Time    string
+	Error   string
+	Array   string
+	Slice   string
+	Map     string
+	Struct  string
+	Group   string
+	Invalid string
+}
+
+// DefaultMarkers returns the markers used when Options.Markers is nil.
+func DefaultMarkers() *Markers {
+	return &Markers{
+		Number:  "#",
+		Bool:    "#",
+		URL:     "*",
+		Time:    "@",
+		Error:   "E",
+		Array:   "A",
+		Slice:   "S",
+		Map:     "M",
+		Struct:  "S",
+		Group:   "G",
+		Invalid: "!",
+	}
+}
+
+func mergeMarkers(m *Markers) *Markers {
+	d := DefaultMarkers()
+	if m == nil {
+		return d
+	}
+
+	r := *m
+	for _, f := range []struct {
+		v *string
+		d string
+	}{
+		{&r.Number, d.Number},
+		{&r.Bool, d.Bool},
+		{&r.URL, d.URL},
+		{&r.Time, d.Time},
+		{&r.Error, d.Error},
+		{&r.Array, d.Array},
+		{&r.Slice, d.Slice},
+		{&r.Map, d.Map},
+		{&r.Struct, d.Struct},
+		{&r.Group, d.Group},
+		{&r.Invalid, d.Invalid},
+	} {
+		if *f.v == "" {
+			*f.v = f.d
+		}
+	}
+
+	return &r
+}
+
+// mark is a rendered marker together with its width on the terminal.
+type mark struct {
+	b     []byte
+	width int
+}
+
+// marker renders glyph, or the textual type label in accessible mode.
+func (h *developHandler) marker(glyph string, label string, c foregroundColor) mark {
+	s := glyph
+	if h.opts.Accessible {
+		s = label
+	}
+
+	return mark{
+		b:     h.colorString([]byte(s), c),
+		width: stringWidth(s),
+	}
+}
+
+// withTypeLabel appends the type label to a nested value in accessible mode.
+func (h *developHandler) withTypeLabel(b []byte, label string) []byte {
+	if !h.opts.Accessible {
+		return b
+	}
+
+	return append(b, h.colorStringFainted([]byte(" ("+label+")"), h.pal.muted)...)
+}
+
+// legend describes the markers, or type labels in accessible mode, with
+// their colors.
+func (h *developHandler) legend() []byte {
+	m := h.opts.Markers
+	entries := []struct {
+		glyph   string
+		label   string
+		c       foregroundColor
+		meaning string
+	}{
+		{m.Number, "int, uint, float", h.pal.number, "number"},
+		{m.Bool, "bool", h.pal.bool, "boolean"},
+		{m.URL, "url", h.pal.url, "URL"},
+		{m.Time, "time, duration", h.pal.time, "time or duration"},
+		{m.Error, "error", h.pal.err, "error"},
+		{m.Array, "[N]T", h.pal.collectionMarker, "array"},
+		{m.Slice, "[]T", h.pal.collectionMarker, "slice"},
+		{m.Map, "map[K]V", h.pal.collectionMarker, "map"},
+		{m.Struct, "T", h.pal.structMarker, "struct"},
+		{m.Group, "group", h.pal.collectionMarker, "group"},
+		{m.Invalid, "nil", h.pal.err, "nil or unknown type"},
+	}
+
+	marks := make([]mark, len(entries))
+	width := 0
+	for i, e := range entries {
+		marks[i] = h.marker(e.glyph, e.label, e.c)
+		width = max(width, marks[i].width)
+	}
+
+	b := h.colorStringFainted([]byte("Legend"), h.pal.muted)
+	b = append(b, '\n')
+	for i, e := range entries {
+		b = append(b, ' ', ' ')
+		b = append(b, marks[i].b...)
+		b = append(b, bytes.Repeat([]byte(" "), width-marks[i].width)...)
+		b = append(b, ' ')
+		b = append(b, e.meaning...)
+		b = append(b, '\n')
+	}
+
+	return append(b, '\n')
+}
@@ -0,0 +1,79 @@
+package devslog
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+)
+
+func TestMarkers(t *testing.T) {
+	testMarkersCustom(t)
+	testAccessible(t)
+	testLegend(t)
+}
+
+func testMarkersCustom(t *testing.T) {
+	w := &MockWriter{}
+	opts := &Options{
+		TimeFormat: "[]",
+		NoColor:    true,
+		Markers:    &Markers{Number: "num", Error: "✗"},
+	}
+
+	logger := slog.New(NewHandler(w, opts))
+	logger.Info("msg",
+		slog.Int("i", 1),
+		slog.Bool("b", true),
+		slog.String("s", "x"),
+	)
+
+	expected := []byte("[]  INFO  msg\nnum i: 1\n#   b: true\n    s: x\n")
+
+	if !bytes.Equal(w.WrittenData, expected) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, w.WrittenData)
+	}
+}
+
+func testAccessible(t *testing.T) {
+	w := &MockWriter{}
+	opts := &Options{
+		TimeFormat: "[]",
+		NoColor:    true,
+		Accessible: true,
+		SortKeys:   true,
+	}
+
+	logger := slog.New(NewHandler(w, opts))
+	logger.Info("msg",
+		slog.Int("i", 1),
+		slog.Bool("b", true),
+		slog.String("u", "https://go.dev/"),
+		slog.Any("m", map[string]int{"a": 1}),
+		slog.Group("g", slog.Float64("f", 1.5)),
+	)
+
+	expected := []byte("[]  INFO  msg\nbool           b: true\nint            i: 1\nmap[string]int m: 1 map[string]int\n    a: 1 (int)\nurl            u: https://go.dev/\ngroup          g: \n  float f: 1.5\n")
+
+	if !bytes.Equal(w.WrittenData, expected) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, w.WrittenData)
+	}
+}
+
+func testLegend(t *testing.T) {
+	w := &MockWriter{}
+	opts := &Options{
+		TimeFormat: "[]",
+		NoColor:    true,
+		ShowLegend: true,
+	}
+
+	logger := slog.New(NewHandler(w, opts))
+	logger.Info("first")
+	logger.With("a", 1).Info("second")
+
+	expected := []byte("Legend\n  # number\n  # boolean\n  * URL\n  @ time or duration\n  E error\n  A array\n  S slice\n  M map\n  S struct\n  G group\n  ! nil or unknown type\n\n[]  INFO  first\n[]  INFO  second\n# a: 1\n")
+
+	if !bytes.Equal(w.WrittenData, expected) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, w.WrittenData)
+	}
+}
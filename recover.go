@@ -0,0 +1,189 @@
+package devslog
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Recover recovers a panic of the calling goroutine and logs it at Error
+// level with the panic value and the stack of the goroutine. It must be
+// called directly by defer:
+//
+//	defer devslog.Recover(logger)
+func Recover(logger *slog.Logger) {
+	if v := recover(); v != nil {
+		logPanic(context.Background(), logger, v, debug.Stack())
+	}
+}
+
+// RecoverMiddleware returns an HTTP middleware which recovers panics of the
+// handlers, logs them like Recover with the method and the path of the
+// request and responds with 500 Internal Server Error. http.ErrAbortHandler
+// is passed on to the server.
+func RecoverMiddleware(logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				v := recover()
+				if v == nil {
+					return
+				}
+
+				if v == http.ErrAbortHandler {
+					panic(v)
+				}
+
+				logPanic(r.Context(), logger, v, debug.Stack(),
+					slog.String("method", r.Method),
+					slog.String("path", r.URL.Path),
+				)
+
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// logPanic logs the recovered value v with the stack from debug.Stack. The
+// source of the record is the function which panicked.
+func logPanic(ctx context.Context, logger *slog.Logger, v any, stack []byte, attrs ...slog.Attr) {
+	if !logger.Enabled(ctx, slog.LevelError) {
+		return
+	}
+
+	r := slog.NewRecord(time.Now(), slog.LevelError, fmt.Sprintf("panic: %v", v), panicPC())
+	r.AddAttrs(slog.Any("panic", v), slog.Any("stack", parseStack(stack)))
+	r.AddAttrs(attrs...)
+
+	_ = logger.Handler().Handle(ctx, r)
+}
+
+// panicPC returns the program counter of the function which called panic or
+// caused a runtime error, or 0 if the goroutine is not panicking.
+func panicPC() uintptr {
+	pcs := make([]uintptr, maxStackTraceFrames)
+	pcs = pcs[:runtime.Callers(2, pcs)]
+
+	frames := runtime.CallersFrames(pcs)
+	for {
+		fr, more := frames.Next()
+		if fr.Function == "runtime.gopanic" {
+			break
+		}
+
+		if !more {
+			return 0
+		}
+	}
+
+	// Runtime errors panic from within the runtime, e.g. runtime.panicmem
+	for {
+		fr, more := frames.Next()
+		if !isRuntimeFunction(fr.Function) {
+			return fr.PC + 1
+		}
+
+		if !more {
+			return 0
+		}
+	}
+}
+
+// panicStack is the stack of a goroutine parsed from debug.Stack. Handlers
+// other than this one log it as the original text.
+type panicStack struct {
+	goroutine int
+	frames    []runtime.Frame
+	raw       []byte
+}
+
+func (s *panicStack) MarshalText() ([]byte, error) {
+	return s.raw, nil
+}
+
+// parseStack parses the output of debug.Stack, keeping only the frames below
+// the call of panic, if any.
+//
+//	goroutine 7 [running]:
+//	runtime/debug.Stack()
+//		/usr/local/go/src/runtime/debug/stack.go:26 +0x5e
+//	main.load(0x2a)
+//		/app/main.go:12 +0x1d
+//	created by main.start in goroutine 1
+//		/app/main.go:30 +0x45
+//
+// A frame is a function line followed by a tab-indented location line. Other
+// lines, such as "...additional frames elided..." of deep stacks, are skipped.
+func parseStack(stack []byte) *panicStack {
+	s := &panicStack{raw: bytes.TrimSpace(stack)}
+
+	lines := strings.Split(string(s.raw), "\n")
+	if len(lines) > 0 {
+		id, _, _ := strings.Cut(strings.TrimPrefix(lines[0], "goroutine "), " ")
+		s.goroutine, _ = strconv.Atoi(id)
+		lines = lines[1:]
+	}
+
+	for i := 0; i+1 < len(lines); i++ {
+		fn := lines[i]
+		if fn == "" || strings.HasPrefix(fn, "\t") || !strings.HasPrefix(lines[i+1], "\t") {
+			continue
+		}
+
+		location := strings.TrimSpace(lines[i+1])
+		location, _, _ = strings.Cut(location, " +0x")
+		j := strings.LastIndex(location, ":")
+		if j <= 0 {
+			continue
+		}
+
+		line, err := strconv.Atoi(location[j+1:])
+		if err != nil {
+			continue
+		}
+
+		file := location[:j]
+		i++
+
+		if created, ok := strings.CutPrefix(fn, "created by "); ok {
+			fn, _, _ = strings.Cut(created, " in goroutine ")
+		} else if j := strings.LastIndex(fn, "("); j > 0 && strings.HasSuffix(fn, ")") {
+			fn = fn[:j]
+		}
+
+		if fn == "panic" {
+			s.frames = s.frames[:0]
+			continue
+		}
+
+		s.frames = append(s.frames, runtime.Frame{Function: fn, File: file, Line: line})
+	}
+
+	frames := s.frames[:0]
+	for _, fr := range s.frames {
+		if !isRuntimeFunction(fr.Function) && !strings.HasPrefix(fr.Function, "runtime/debug.") {
+			frames = append(frames, fr)
+		}
+	}
+
+	s.frames = frames
+
+	return s
+}
+
+// formatPanicStack renders the goroutine id followed by the frames.
+func (h *developHandler) formatPanicStack(s *panicStack, l int) (b []byte) {
+	b = append(b, h.colorString([]byte("goroutine "+strconv.Itoa(s.goroutine)), h.pal.stackFrame)...)
+
+	return h.formatFrameList(b, h.frameRows(s.frames), l*2+4)
+}
@@ -0,0 +1,198 @@
+package devslog
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestRecover(t *testing.T) {
+	testParseStack(t)
+	testParseStackElided(t)
+	testRecover(t)
+	testRecoverRuntimeError(t)
+	testRecoverMiddleware(t)
+}
+
+func testParseStack(t *testing.T) {
+	stack := []byte(`goroutine 7 [running]:
+runtime/debug.Stack()
+	/usr/local/go/src/runtime/debug/stack.go:26 +0x5e
+github.com/golang-cz/devslog.Recover(0xc000120000)
+	/src/devslog/recover.go:22 +0x45
+panic({0x4a2b40?, 0x5c3f20?})
+	/usr/local/go/src/runtime/panic.go:770 +0x132
+main.(*Store).load(...)
+	/app/store.go:12
+main.main()
+	/app/main.go:20 +0x25
+created by main.start in goroutine 1
+	/app/main.go:30 +0x45
+`)
+
+	s := parseStack(stack)
+	expected := []runtime.Frame{
+		{Function: "main.(*Store).load", File: "/app/store.go", Line: 12},
+		{Function: "main.main", File: "/app/main.go", Line: 20},
+		{Function: "main.start", File: "/app/main.go", Line: 30},
+	}
+
+	if s.goroutine != 7 {
+		t.Errorf("goroutine = %d, want 7", s.goroutine)
+	}
+
+	if len(s.frames) != len(expected) {
+		t.Fatalf("frames = %v, want %v", s.frames, expected)
+	}
+
+	for i := range expected {
+		if s.frames[i] != expected[i] {
+			t.Errorf("frame %d = %v, want %v", i, s.frames[i], expected[i])
+		}
+	}
+
+	w := &MockWriter{}
+	logger := slog.New(NewHandler(w, &Options{TimeFormat: "[]", NoColor: true}))
+	logger.Error("msg", slog.Any("stack", s))
+
+	want := []byte("[]  ERROR  msg\nE stack: goroutine 7\n    0: main.(*Store).load /app/store.go:12\n    1: main.main /app/main.go:20\n    2: main.start /app/main.go:30\n")
+
+	if !bytes.Equal(w.WrittenData, want) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", want, w.WrittenData)
+	}
+}
+
+func testParseStackElided(t *testing.T) {
+	stack := []byte(`goroutine 1 [running]:
+runtime/debug.Stack()
+	/usr/local/go/src/runtime/debug/stack.go:26 +0x5e
+panic({0x4a2b40?, 0x5c3f20?})
+	/usr/local/go/src/runtime/panic.go:770 +0x132
+main.rec(0x0)
+	/app/main.go:14 +0x25
+main.rec(0x1)
+	/app/main.go:14 +0x25
+...additional frames elided...
+main.rec(0x95)
+	/app/main.go:14 +0x25
+main.main()
+	/app/main.go:20 +0x25
+created by main.start in goroutine 1
+	/app/main.go:30 +0x45
+`)
+
+	s := parseStack(stack)
+	expected := []runtime.Frame{
+		{Function: "main.rec", File: "/app/main.go", Line: 14},
+		{Function: "main.rec", File: "/app/main.go", Line: 14},
+		{Function: "main.rec", File: "/app/main.go", Line: 14},
+		{Function: "main.main", File: "/app/main.go", Line: 20},
+		{Function: "main.start", File: "/app/main.go", Line: 30},
+	}
+
+	if len(s.frames) != len(expected) {
+		t.Fatalf("frames = %v, want %v", s.frames, expected)
+	}
+
+	for i := range expected {
+		if s.frames[i] != expected[i] {
+			t.Errorf("frame %d = %v, want %v", i, s.frames[i], expected[i])
+		}
+	}
+}
+
+func panicking() {
+	panic(errors.New("boom"))
+}
+
+func testRecover(t *testing.T) {
+	w := &MockWriter{}
+	o := &Options{
+		HandlerOptions: &slog.HandlerOptions{AddSource: true},
+		TimeFormat:     "[]",
+		NoColor:        true,
+		SourcePath:     SourcePathRelative,
+	}
+
+	logger := slog.New(NewHandler(w, o))
+	func() {
+		defer Recover(logger)
+		panicking()
+	}()
+
+	out := string(w.WrittenData)
+	for _, s := range []string{
+		"[] @@@ recover_test.go:",
+		" ERROR  panic: boom\n",
+		"E panic: \n    0: boom\n",
+		"E stack: goroutine ",
+		"    0: devslog.panicking recover_test.go:",
+	} {
+		if !strings.Contains(out, s) {
+			t.Errorf("output does not contain %q:\n%s", s, out)
+		}
+	}
+
+	if strings.Contains(out, "devslog.Recover") || strings.Contains(out, "runtime/debug") {
+		t.Errorf("stack contains the frames of Recover:\n%s", out)
+	}
+}
+
+func dereferencing(p *cycleTestNode) string {
+	return p.Name
+}
+
+func testRecoverRuntimeError(t *testing.T) {
+	w := &MockWriter{}
+	o := &Options{
+		HandlerOptions: &slog.HandlerOptions{AddSource: true},
+		TimeFormat:     "[]",
+		NoColor:        true,
+		SourcePath:     SourcePathRelative,
+	}
+
+	logger := slog.New(NewHandler(w, o))
+	func() {
+		defer Recover(logger)
+		dereferencing(nil)
+	}()
+
+	out := string(w.WrittenData)
+	for _, s := range []string{
+		"[] @@@ recover_test.go:",
+		" ERROR  panic: runtime error: invalid memory address or nil pointer dereference\n",
+		"    0: devslog.dereferencing recover_test.go:",
+	} {
+		if !strings.Contains(out, s) {
+			t.Errorf("output does not contain %q:\n%s", s, out)
+		}
+	}
+}
+
+func testRecoverMiddleware(t *testing.T) {
+	w := &MockWriter{}
+	logger := slog.New(NewHandler(w, &Options{TimeFormat: "[]", NoColor: true}))
+
+	h := RecoverMiddleware(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("nil map")
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users", nil))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want 500", rec.Code)
+	}
+
+	out := string(w.WrittenData)
+	for _, s := range []string{" ERROR  panic: nil map\n", "  panic : nil map\n", "  method: GET\n", "path  : /users\n"} {
+		if !strings.Contains(out, s) {
+			t.Errorf("output does not contain %q:\n%s", s, out)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package devslog
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Lines of source printed before and after the logged line by default.
+const defaultSnippetLines = 2
+
+// sourceCache holds the lines of source files read for snippets. Files which
+// cannot be read are cached as nil, so they are not tried again.
+type sourceCache struct {
+	mu    sync.Mutex
+	files map[string][]string
+}
+
+func newSourceCache() *sourceCache {
+	return &sourceCache{files: map[string][]string{}}
+}
+
+func (c *sourceCache) lines(file string) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if lines, ok := c.files[file]; ok {
+		return lines
+	}
+
+	var lines []string
+	if data, err := os.ReadFile(file); err == nil {
+		lines = strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	}
+
+	c.files[file] = lines
+
+	return lines
+}
+
+// sourceSnippet renders the lines of source around the log call of records
+// at SnippetLevel or above. It returns nil when the source file is not
+// available.
+func (h *developHandler) sourceSnippet(r *slog.Record) []byte {
+	if !h.opts.AddSource || h.opts.SnippetLevel == nil || r.Level < h.opts.SnippetLevel.Level() || r.PC == 0 {
+		return nil
+	}
+
+	s := h.source(r)
+	if s == nil {
+		return nil
+	}
+
+	return h.snippet(s.File, s.Line)
+}
+
+// snippet renders the lines of file around line, highlighting line.
+func (h *developHandler) snippet(file string, line int) (b []byte) {
+	lines := h.sources.lines(file)
+	if line < 1 || line > len(lines) {
+		return nil
+	}
+
+	n := int(h.opts.SnippetLines)
+	if n == 0 {
+		n = defaultSnippetLines
+	}
+
+	from := max(line-n, 1)
+	to := min(line+n, len(lines))
+	d := len(strconv.Itoa(to))
+
+	for i := from; i <= to; i++ {
+		tb := strconv.Itoa(i)
+		text := []byte(strings.ReplaceAll(strings.TrimRight(lines[i-1], " \t"), "\t", "    "))
+
+		if i == line {
+			b = append(b, ' ', ' ')
+			b = append(b, h.colorString([]byte(">"), h.pal.sourceMarker)...)
+			b = append(b, ' ')
+			b = append(b, bytes.Repeat([]byte(" "), d-len(tb))...)
+			b = append(b, h.colorString([]byte(tb), h.pal.sourceLine)...)
+			b = append(b, h.faintedText([]byte(" |"))...)
+			if len(text) > 0 {
+				b = append(b, ' ')
+				b = append(b, text...)
+			}
+		} else {
+			b = append(b, bytes.Repeat([]byte(" "), 4+d-len(tb))...)
+			b = append(b, h.faintedText([]byte(tb+" |"))...)
+			if len(text) > 0 {
+				b = append(b, ' ')
+				b = append(b, h.faintedText(text)...)
+			}
+		}
+
+		b = append(b, '\n')
+	}
+
+	return b
+}
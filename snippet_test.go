@@ -0,0 +1,138 @@
+package devslog
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+)
+
+func TestSnippet(t *testing.T) {
+	testSnippet(t)
+	testSnippetEdges(t)
+	testSnippetMissingFile(t)
+	testSnippetLevel(t)
+	testSnippetHeaderCompact(t)
+}
+
+func testSnippet(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "main.go")
+	if err := os.WriteFile(file, []byte("package main\n\nfunc main() {\n\tslog.Error(\"msg\")   \n}\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	h := NewHandler(nil, &Options{NoColor: true, SnippetLines: 1})
+
+	expected := []byte("    3 | func main() {\n  > 4 |     slog.Error(\"msg\")\n    5 | }\n")
+	if got := h.snippet(file, 4); !bytes.Equal(got, expected) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, got)
+	}
+
+	h = NewHandler(nil, &Options{ColorMode: ColorAlways, SnippetLines: 1})
+
+	expected = []byte("    \x1b[2m1 |\x1b[0m \x1b[2mpackage main\x1b[0m\n  \x1b[33m>\x1b[0m \x1b[31m2\x1b[0m\x1b[2m |\x1b[0m\n    \x1b[2m3 |\x1b[0m \x1b[2mfunc main() {\x1b[0m\n")
+	if got := h.snippet(file, 2); !bytes.Equal(got, expected) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, got)
+	}
+}
+
+func testSnippetEdges(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "lines.txt")
+	if err := os.WriteFile(file, []byte("1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	h := NewHandler(nil, &Options{NoColor: true})
+
+	expected := []byte("  > 1 | 1\n    2 | 2\n    3 | 3\n")
+	if got := h.snippet(file, 1); !bytes.Equal(got, expected) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, got)
+	}
+
+	expected = []byte("     8 | 8\n     9 | 9\n  > 10 | 10\n    11 | 11\n")
+	if got := h.snippet(file, 10); !bytes.Equal(got, expected) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, got)
+	}
+
+	if got := h.snippet(file, 12); got != nil {
+		t.Errorf("snippet past the end of file = %q, want nil", got)
+	}
+}
+
+func testSnippetMissingFile(t *testing.T) {
+	h := NewHandler(nil, &Options{NoColor: true})
+	file := filepath.Join(t.TempDir(), "missing.go")
+
+	if got := h.snippet(file, 1); got != nil {
+		t.Errorf("snippet of a missing file = %q, want nil", got)
+	}
+
+	if _, ok := h.sources.files[file]; !ok {
+		t.Errorf("missing file is not cached")
+	}
+}
+
+func testSnippetLevel(t *testing.T) {
+	w := &MockWriter{}
+	o := &Options{
+		HandlerOptions: &slog.HandlerOptions{AddSource: true},
+		TimeFormat:     "[]",
+		NoColor:        true,
+		SourcePath:     SourcePathRelative,
+		SnippetLevel:   slog.LevelWarn,
+		SnippetLines:   1,
+	}
+
+	logger := slog.New(NewHandler(w, o))
+	logger.Info("msg")
+
+	if bytes.Contains(w.WrittenData, []byte(" | ")) {
+		t.Errorf("snippet printed below SnippetLevel:\n%s", w.WrittenData)
+	}
+
+	w.WrittenData = nil
+
+	// Capture the line number right before the log call
+	_, _, line, _ := runtime.Caller(0)
+	logger.Warn("msg")
+
+	line++
+
+	d := len(strconv.Itoa(line + 1))
+	expected := fmt.Sprintf("[] @@@ snippet_test.go:%[1]d\n    %[4]*[2]d |     _, _, line, _ := runtime.Caller(0)\n  > %[4]*[1]d |     logger.Warn(\"msg\")\n    %[4]*[3]d |\n WARN  msg\n", line, line-1, line+1, d)
+
+	if !bytes.Equal(w.WrittenData, []byte(expected)) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, w.WrittenData)
+	}
+}
+
+func testSnippetHeaderCompact(t *testing.T) {
+	w := &MockWriter{}
+	o := &Options{
+		HandlerOptions: &slog.HandlerOptions{AddSource: true},
+		HeaderFormat:   "{level} {msg}",
+		NoColor:        true,
+		Compact:        true,
+		SnippetLevel:   slog.LevelWarn,
+		SnippetLines:   1,
+	}
+
+	logger := slog.New(NewHandler(w, o))
+
+	// Capture the line number right before the log call
+	_, _, line, _ := runtime.Caller(0)
+	logger.Warn("msg", "k", 1, "s", "v")
+
+	line++
+
+	d := len(strconv.Itoa(line + 1))
+	expected := fmt.Sprintf(" WARN  msg k=1 s=v\n    %[4]*[2]d |     _, _, line, _ := runtime.Caller(0)\n  > %[4]*[1]d |     logger.Warn(\"msg\", \"k\", 1, \"s\", \"v\")\n    %[4]*[3]d |\n", line, line-1, line+1, d)
+
+	if !bytes.Equal(w.WrittenData, []byte(expected)) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, w.WrittenData)
+	}
+}
@@ -0,0 +1,154 @@
+package devslog
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+// SourcePath controls how file paths of source info and stack frames are printed.
+type SourcePath uint
+
+const (
+	// SourcePathAbsolute prints absolute paths as resolved by the runtime.
+	SourcePathAbsolute SourcePath = iota
+
+	// SourcePathRelative prints paths relative to the working directory.
+	// Files outside of it are printed as with SourcePathShort.
+	SourcePathRelative
+
+	// SourcePathModule prints paths relative to the root of the main module,
+	// the nearest directory with go.mod above the working directory. Files
+	// outside of it are printed as with SourcePathShort.
+	SourcePathModule
+
+	// SourcePathShort trims the GOROOT, GOPATH and module cache prefixes,
+	// leaving the import path of the package followed by the file name.
+	SourcePathShort
+)
+
+// pathShortener rewrites absolute file paths according to a SourcePath mode.
+type pathShortener struct {
+	base     string
+	module   string
+	prefixes []string
+}
+
+func newPathShortener(mode SourcePath) *pathShortener {
+	if mode == SourcePathAbsolute {
+		return nil
+	}
+
+	ps := &pathShortener{prefixes: trimPrefixes()}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return ps
+	}
+
+	switch mode {
+	case SourcePathRelative:
+		ps.base = wd
+	case SourcePathModule:
+		ps.base = moduleRoot(wd)
+		if bi, ok := debug.ReadBuildInfo(); ok && bi.Main.Path != "" {
+			ps.module = bi.Main.Path + "/"
+		}
+	}
+
+	return ps
+}
+
+// shorten returns the path to print for file. A nil shortener keeps it as is.
+func (ps *pathShortener) shorten(file string) string {
+	if ps == nil || file == "" {
+		return file
+	}
+
+	if ps.base != "" {
+		if rel, err := filepath.Rel(ps.base, file); err == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.ToSlash(rel)
+		}
+	}
+
+	// Binaries built with -trimpath have file paths starting with the module path
+	if ps.module != "" && strings.HasPrefix(file, ps.module) {
+		return file[len(ps.module):]
+	}
+
+	for _, p := range ps.prefixes {
+		if strings.HasPrefix(file, p) {
+			return file[len(p):]
+		}
+	}
+
+	return file
+}
+
+// moduleRoot returns the nearest directory above dir containing go.mod, or "".
+func moduleRoot(dir string) string {
+	for {
+		if fi, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil && !fi.IsDir() {
+			return dir
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+
+		dir = parent
+	}
+}
+
+// trimPrefixes returns the module cache, GOPATH and GOROOT source directories,
+// most specific first, each ending with a slash.
+func trimPrefixes() (prefixes []string) {
+	add := func(dir string) {
+		if dir != "" {
+			prefixes = append(prefixes, filepath.ToSlash(filepath.Clean(dir))+"/")
+		}
+	}
+
+	gopath := filepath.SplitList(os.Getenv("GOPATH"))
+	if len(gopath) == 0 {
+		if home, err := os.UserHomeDir(); err == nil {
+			gopath = []string{filepath.Join(home, "go")}
+		}
+	}
+
+	if modcache := os.Getenv("GOMODCACHE"); modcache != "" {
+		add(modcache)
+	} else if len(gopath) > 0 {
+		add(filepath.Join(gopath[0], "pkg", "mod"))
+	}
+
+	for _, p := range gopath {
+		add(filepath.Join(p, "src"))
+	}
+
+	// The standard library is compiled with GOROOT/src paths, find them from one of its functions
+	f := runtime.FuncForPC(reflect.ValueOf(strings.Cut).Pointer())
+	if f != nil {
+		file, _ := f.FileLine(f.Entry())
+		if i := strings.LastIndex(file, "/strings/"); i >= 0 {
+			prefixes = append(prefixes, file[:i+1])
+		}
+	}
+
+	return prefixes
+}
+
+// shortFunction trims the package path from a function name of runtime.Frame,
+// e.g. github.com/golang-cz/devslog.(*developHandler).Handle becomes
+// devslog.(*developHandler).Handle.
+func shortFunction(fn string) string {
+	if i := strings.LastIndex(fn, "/"); i >= 0 {
+		return fn[i+1:]
+	}
+
+	return fn
+}
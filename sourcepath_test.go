@@ -0,0 +1,124 @@
+package devslog
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestSourcePath(t *testing.T) {
+	testSourcePathRelative(t)
+	testSourcePathModule(t)
+	testSourcePathShorten(t)
+	testSourceFunction(t)
+	testShortFunction(t)
+}
+
+func testSourcePathRelative(t *testing.T) {
+	w := &MockWriter{}
+	o := &Options{
+		HandlerOptions: &slog.HandlerOptions{AddSource: true},
+		TimeFormat:     "[]",
+		NoColor:        true,
+		SourcePath:     SourcePathRelative,
+	}
+
+	logger := slog.New(NewHandler(w, o))
+
+	// Capture the line number right before the log call
+	_, _, line, _ := runtime.Caller(0)
+	logger.Info("msg")
+
+	line++
+
+	expected := fmt.Sprintf("[] @@@ sourcepath_test.go:%d\n INFO  msg\n", line)
+
+	if !bytes.Equal(w.WrittenData, []byte(expected)) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, w.WrittenData)
+	}
+}
+
+func testSourcePathModule(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if root := moduleRoot(filepath.Join(wd, "testdata")); root != wd {
+		t.Errorf("moduleRoot = %q, want %q", root, wd)
+	}
+
+	ps := newPathShortener(SourcePathModule)
+	if file := ps.shorten(filepath.Join(wd, "devslog.go")); file != "devslog.go" {
+		t.Errorf("shorten in module = %q, want devslog.go", file)
+	}
+}
+
+func testSourcePathShorten(t *testing.T) {
+	ps := &pathShortener{
+		module:   "github.com/golang-cz/devslog/",
+		prefixes: []string{"/home/ci/go/pkg/mod/", "/home/ci/go/src/", "/usr/local/go/src/"},
+	}
+
+	cases := map[string]string{
+		"/home/ci/go/pkg/mod/github.com/pkg/errors@v0.9.1/errors.go": "github.com/pkg/errors@v0.9.1/errors.go",
+		"/home/ci/go/src/example.com/app/main.go":                    "example.com/app/main.go",
+		"/usr/local/go/src/net/http/server.go":                       "net/http/server.go",
+		"github.com/golang-cz/devslog/devslog.go":                    "devslog.go",
+		"/opt/app/main.go": "/opt/app/main.go",
+	}
+
+	for in, expected := range cases {
+		if out := ps.shorten(in); out != expected {
+			t.Errorf("shorten(%q) = %q, want %q", in, out, expected)
+		}
+	}
+
+	var abs *pathShortener
+	if out := abs.shorten("/opt/app/main.go"); out != "/opt/app/main.go" {
+		t.Errorf("nil shorten = %q, want the path unchanged", out)
+	}
+}
+
+func testSourceFunction(t *testing.T) {
+	w := &MockWriter{}
+	o := &Options{
+		HandlerOptions: &slog.HandlerOptions{AddSource: true},
+		TimeFormat:     "[]",
+		NoColor:        true,
+		SourcePath:     SourcePathModule,
+		SourceFunction: true,
+	}
+
+	logger := slog.New(NewHandler(w, o))
+
+	// Capture the line number right before the log call
+	_, _, line, _ := runtime.Caller(0)
+	logger.Info("msg")
+
+	line++
+
+	expected := fmt.Sprintf("[] @@@ sourcepath_test.go:%d devslog.testSourceFunction\n INFO  msg\n", line)
+
+	if !bytes.Equal(w.WrittenData, []byte(expected)) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, w.WrittenData)
+	}
+}
+
+func testShortFunction(t *testing.T) {
+	cases := map[string]string{
+		"main.main": "main.main",
+		"github.com/golang-cz/devslog.(*developHandler).Handle": "devslog.(*developHandler).Handle",
+		"net/http.HandlerFunc.ServeHTTP":                        "http.HandlerFunc.ServeHTTP",
+	}
+
+	for in, expected := range cases {
+		if out := shortFunction(in); out != expected {
+			t.Errorf("shortFunction(%q) = %q, want %q", in, out, expected)
+		}
+	}
+}
@@ -1,12 +1,16 @@
 package devslog
 
 import (
-	"fmt"
+	"bytes"
+	"log/slog"
 	"reflect"
 	"runtime"
+	"slices"
+	"strconv"
+	"strings"
 )
 
-func (h *developHandler) getFileLineFromPC(pcs []uintptr) (fileLines []string) {
+func (h *developHandler) getFramesFromPC(pcs []uintptr) (frs []runtime.Frame) {
 	if len(pcs) == 0 {
 		return nil
 	}
@@ -14,36 +18,49 @@ func (h *developHandler) getFileLineFromPC(pcs []uintptr) (fileLines []string) {
 	frames := runtime.CallersFrames(pcs[:])
 	for {
 		fr, more := frames.Next()
-		fileLines = append(fileLines, fmt.Sprintf("%v:%v", fr.File, fr.Line))
+		frs = append(frs, fr)
 		if !more {
 			break
 		}
 	}
 
-	return fileLines
+	return frs
 }
 
-// extractPCFromError tries to extract StackTrace PC frames from errors created by
+// extractFramesFromError tries to extract StackTrace frames from errors
+// known to a registered StackExtractor or created by
 // - github.com/pkg/errors
+// - github.com/go-errors/errors
+// - github.com/cockroachdb/errors
 // - golang.org/x/xerrors
 // - golang.org/x/exp/errors
-func (h *developHandler) extractPCFromError(err error) (pc []uintptr) {
+// - any error with Callers() []uintptr or StackTrace() []runtime.Frame
+func (h *developHandler) extractFramesFromError(err error) []runtime.Frame {
 	if h.opts.MaxErrorStackTrace == 0 {
 		return nil
 	}
 
-	v := reflect.ValueOf(err)
-	pc = h.extractPCFromPkgErrors(v)
-	if len(pc) > 0 {
-		return pc
+	n := int(h.opts.MaxErrorStackTrace)
+	if pc := registeredStack(err); len(pc) > 0 {
+		return h.getFramesFromPC(pc[:min(len(pc), n)])
 	}
 
-	pc = h.extractPCFromExpErrors(v)
-	if len(pc) > 0 {
-		return pc
+	switch e := err.(type) {
+	case callersError:
+		pc := e.Callers()
+		return h.getFramesFromPC(pc[:min(len(pc), n)])
+	case framesError:
+		frs := e.StackTrace()
+		return frs[:min(len(frs), n)]
 	}
 
-	return pc
+	// github.com/cockroachdb/errors records the stack in the same format as github.com/pkg/errors
+	v := reflect.ValueOf(err)
+	if pc := h.extractPCFromPkgErrors(v); len(pc) > 0 {
+		return h.getFramesFromPC(pc)
+	}
+
+	return h.getFramesFromPC(h.extractPCFromExpErrors(v))
 }
 
 func (h *developHandler) extractPCFromPkgErrors(v reflect.Value) (pc []uintptr) {
@@ -134,3 +151,81 @@ func (h *developHandler) extractPCFromExpErrors(v reflect.Value) (pc []uintptr)
 
 	return pc
 }
+
+// Maximum number of frames captured for StackTraceLevel.
+const maxStackTraceFrames = 64
+
+// callerFrames returns the frames of the current goroutine starting at the
+// log call of the record, without the frames of log/slog and the runtime.
+func (h *developHandler) callerFrames(r *slog.Record) (frs []runtime.Frame) {
+	pcs := make([]uintptr, maxStackTraceFrames)
+	pcs = pcs[:runtime.Callers(2, pcs)]
+
+	start := slices.Index(pcs, r.PC)
+	if start < 0 {
+		// Without the PC of the log call, skip the frames of the handler and log/slog
+		for start = 0; start < len(pcs); start++ {
+			fn := runtime.FuncForPC(pcs[start] - 1)
+			if fn == nil || !isLoggingFunction(fn.Name()) {
+				break
+			}
+		}
+	}
+
+	for _, fr := range h.getFramesFromPC(pcs[start:]) {
+		if isRuntimeFunction(fr.Function) || strings.HasPrefix(fr.Function, "log/slog.") {
+			continue
+		}
+
+		frs = append(frs, fr)
+	}
+
+	return frs
+}
+
+// isLoggingFunction reports whether fn belongs to log/slog or is a method
+// of the handler. Other functions of this package, e.g. in tests, are callers.
+func isLoggingFunction(fn string) bool {
+	return strings.HasPrefix(fn, "log/slog.") || strings.HasPrefix(fn, "github.com/golang-cz/devslog.(*developHandler).")
+}
+
+// isRuntimeFunction reports whether fn belongs to the runtime.
+func isRuntimeFunction(fn string) bool {
+	return strings.HasPrefix(fn, "runtime.")
+}
+
+// formatStackTrace renders the goroutine stack of records at StackTraceLevel
+// or above.
+func (h *developHandler) formatStackTrace(b []byte, r *slog.Record) []byte {
+	if h.opts.StackTraceLevel == nil || r.Level < h.opts.StackTraceLevel.Level() {
+		return b
+	}
+
+	rows := h.frameRows(h.callerFrames(r))
+	if len(rows) == 0 {
+		return b
+	}
+
+	b = append(b, h.colorStringFainted([]byte("stack trace"), h.pal.muted)...)
+	b = append(b, ':')
+
+	b = h.formatFrameList(b, rows, 4)
+
+	return append(b, '\n')
+}
+
+// formatFrameList renders the numbered frames on separate lines indented by
+// indent spaces.
+func (h *developHandler) formatFrameList(b []byte, rows []frameRow, indent int) []byte {
+	d := len(strconv.Itoa(len(rows) - 1))
+	for i, row := range rows {
+		tb := strconv.Itoa(i)
+		b = append(b, '\n')
+		b = append(b, bytes.Repeat([]byte(" "), indent+d-len(tb))...)
+		b = append(b, h.colorString([]byte(tb), h.pal.stackFrame)...)
+		b = append(b, ':', ' ')
+		b = h.formatFrame(b, row)
+	}
+
+	return b
+}
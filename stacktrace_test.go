@@ -0,0 +1,86 @@
+package devslog
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStackTrace(t *testing.T) {
+	testStackTrace(t)
+	testStackTraceLevel(t)
+	testStackTraceWithoutPC(t)
+}
+
+func testStackTrace(t *testing.T) {
+	w := &MockWriter{}
+	o := &Options{
+		TimeFormat:      "[]",
+		NoColor:         true,
+		SourcePath:      SourcePathRelative,
+		StackTraceLevel: slog.LevelError,
+	}
+
+	logger := slog.New(NewHandler(w, o))
+
+	// Capture the line number right before the log call
+	_, _, line, _ := runtime.Caller(0)
+	logger.Error("msg", slog.Int("i", 1))
+
+	line++
+
+	expected := fmt.Sprintf("[]  ERROR  msg\n# i: 1\nstack trace:\n    0: devslog.testStackTrace stacktrace_test.go:%d\n    1: devslog.TestStackTrace stacktrace_test.go:", line)
+
+	if !bytes.HasPrefix(w.WrittenData, []byte(expected)) {
+		t.Errorf("\nExpected prefix:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, w.WrittenData)
+	}
+
+	for _, frame := range []string{"runtime/", "log/slog/"} {
+		if bytes.Contains(w.WrittenData, []byte(frame)) {
+			t.Errorf("stack trace contains %s frames:\n%s", frame, w.WrittenData)
+		}
+	}
+}
+
+func testStackTraceLevel(t *testing.T) {
+	w := &MockWriter{}
+	o := &Options{
+		TimeFormat:      "[]",
+		NoColor:         true,
+		StackTraceLevel: slog.LevelError,
+	}
+
+	logger := slog.New(NewHandler(w, o))
+	logger.Warn("msg")
+
+	expected := []byte("[]  WARN  msg\n")
+
+	if !bytes.Equal(w.WrittenData, expected) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, w.WrittenData)
+	}
+}
+
+func testStackTraceWithoutPC(t *testing.T) {
+	w := &MockWriter{}
+	o := &Options{
+		TimeFormat:      "[]",
+		NoColor:         true,
+		SourcePath:      SourcePathRelative,
+		StackTraceLevel: slog.LevelError,
+	}
+
+	h := NewHandler(w, o)
+	if err := h.Handle(context.Background(), slog.NewRecord(time.Time{}, slog.LevelError, "msg", 0)); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(string(w.WrittenData), "\n")
+	if len(lines) < 3 || lines[1] != "stack trace:" || !strings.HasPrefix(lines[2], "    0: devslog.testStackTraceWithoutPC stacktrace_test.go:") {
+		t.Errorf("stack trace does not start at the caller of the handler:\n%s", w.WrittenData)
+	}
+}
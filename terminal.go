@@ -0,0 +1,146 @@
+package devslog
+
+import (
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ColorMode controls when the handler emits ANSI color codes.
+type ColorMode uint
+
+const (
+	// ColorAuto enables color only when the environment and the writer allow
+	// it. Writers which are not an *os.File are treated as color capable.
+	ColorAuto ColorMode = iota
+
+	// ColorAlways enables color regardless of the environment and the writer.
+	ColorAlways
+
+	// ColorNever disables color.
+	ColorNever
+)
+
+// ColorProfile is the richest color palette supported by the output.
+type ColorProfile uint
+
+const (
+	ProfileNoColor   ColorProfile = iota // no ANSI codes at all
+	ProfileANSI                          // 16 basic colors
+	ProfileANSI256                       // 256 indexed colors
+	ProfileTrueColor                     // 24-bit RGB colors
+)
+
+// DetectColorProfile returns the color profile the handler would use for
+// writer w under the given mode.
+//
+// In ColorAuto mode the following rules apply in order:
+//   - NO_COLOR set to any non-empty value or TERM=dumb disables color,
+//   - FORCE_COLOR (0, 1, 2, 3 or true/false) or CLICOLOR_FORCE != 0 forces color,
+//   - an *os.File that is not a terminal disables color,
+//   - CLICOLOR=0 disables color,
+//   - COLORTERM and TERM select the palette.
+func DetectColorProfile(w io.Writer, mode ColorMode) ColorProfile {
+	switch mode {
+	case ColorNever:
+		return ProfileNoColor
+	case ColorAlways:
+		if p, ok := envForcedColorProfile(); ok && p != ProfileNoColor {
+			return p
+		}
+
+		return envColorProfile()
+	}
+
+	if envDisablesColor() {
+		return ProfileNoColor
+	}
+
+	if p, ok := envForcedColorProfile(); ok {
+		return p
+	}
+
+	if f, ok := w.(*os.File); ok && !isTerminal(f) {
+		return ProfileNoColor
+	}
+
+	if os.Getenv("CLICOLOR") == "0" {
+		return ProfileNoColor
+	}
+
+	return envColorProfile()
+}
+
+// envDisablesColor reports whether the environment signals that ANSI color
+// output should be suppressed. Follows the NO_COLOR convention
+// (https://no-color.org/) — any non-empty value disables color — and the
+// older TERM=dumb convention.
+func envDisablesColor() bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return true
+	}
+	if os.Getenv("TERM") == "dumb" {
+		return true
+	}
+	return false
+}
+
+// envForcedColorProfile reads FORCE_COLOR (https://force-color.org/) and
+// CLICOLOR_FORCE. The second return value reports whether any of them is set.
+func envForcedColorProfile() (ColorProfile, bool) {
+	if fc, ok := os.LookupEnv("FORCE_COLOR"); ok {
+		switch strings.ToLower(fc) {
+		case "0", "false":
+			return ProfileNoColor, true
+		case "2":
+			return max(ProfileANSI256, envColorProfile()), true
+		case "3":
+			return ProfileTrueColor, true
+		default:
+			return envColorProfile(), true
+		}
+	}
+
+	if cf := os.Getenv("CLICOLOR_FORCE"); cf != "" && cf != "0" {
+		return envColorProfile(), true
+	}
+
+	return ProfileNoColor, false
+}
+
+// envColorProfile guesses the palette from COLORTERM and TERM. It never
+// returns ProfileNoColor.
+func envColorProfile() ColorProfile {
+	switch strings.ToLower(os.Getenv("COLORTERM")) {
+	case "truecolor", "24bit":
+		return ProfileTrueColor
+	}
+
+	term := strings.ToLower(os.Getenv("TERM"))
+	switch {
+	case strings.HasSuffix(term, "-truecolor"), strings.HasSuffix(term, "-direct"):
+		return ProfileTrueColor
+	case strings.Contains(term, "256color"):
+		return ProfileANSI256
+	}
+
+	return ProfileANSI
+}
+
+// DetectWidth returns the number of columns of writer w: the size of the
+// terminal if w is one, otherwise the COLUMNS environment variable, or 0 if
+// the width is unknown.
+func DetectWidth(w io.Writer) int {
+	if f, ok := w.(*os.File); ok {
+		if width := terminalWidth(f); width > 0 {
+			return width
+		}
+	}
+
+	if width, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && width > 0 {
+		return width
+	}
+
+	return 0
+}
@@ -0,0 +1,23 @@
+//go:build !linux
+
+package devslog
+
+import (
+	"os"
+)
+
+// isTerminal reports whether f refers to a character device, which is the
+// closest portable approximation of a terminal.
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	return fi.Mode()&os.ModeCharDevice != 0
+}
+
+// terminalWidth is not supported on this platform, COLUMNS is used instead.
+func terminalWidth(f *os.File) int {
+	return 0
+}
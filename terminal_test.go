@@ -0,0 +1,89 @@
+package devslog
+
+import (
+	"os"
+	"testing"
+)
+
+func TestColorProfile(t *testing.T) {
+	testDetectColorProfile(t)
+	testDetectColorProfileFile(t)
+	testNewHandlerColorMode(t)
+}
+
+func testDetectColorProfile(t *testing.T) {
+	cases := []struct {
+		name string
+		mode ColorMode
+		env  map[string]string
+		want ColorProfile
+	}{
+		{"auto no env", ColorAuto, nil, ProfileANSI},
+		{"auto NO_COLOR", ColorAuto, map[string]string{"NO_COLOR": "1"}, ProfileNoColor},
+		{"auto TERM=dumb", ColorAuto, map[string]string{"TERM": "dumb"}, ProfileNoColor},
+		{"auto TERM=xterm-256color", ColorAuto, map[string]string{"TERM": "xterm-256color"}, ProfileANSI256},
+		{"auto COLORTERM=truecolor", ColorAuto, map[string]string{"COLORTERM": "truecolor"}, ProfileTrueColor},
+		{"auto FORCE_COLOR=0", ColorAuto, map[string]string{"FORCE_COLOR": "0"}, ProfileNoColor},
+		{"auto FORCE_COLOR=2", ColorAuto, map[string]string{"FORCE_COLOR": "2"}, ProfileANSI256},
+		{"auto FORCE_COLOR=3", ColorAuto, map[string]string{"FORCE_COLOR": "3"}, ProfileTrueColor},
+		{"auto CLICOLOR=0", ColorAuto, map[string]string{"CLICOLOR": "0"}, ProfileNoColor},
+		{"auto CLICOLOR=0 CLICOLOR_FORCE=1", ColorAuto, map[string]string{"CLICOLOR": "0", "CLICOLOR_FORCE": "1"}, ProfileANSI},
+		{"always NO_COLOR", ColorAlways, map[string]string{"NO_COLOR": "1"}, ProfileANSI},
+		{"always FORCE_COLOR=0", ColorAlways, map[string]string{"FORCE_COLOR": "0"}, ProfileANSI},
+		{"never", ColorNever, map[string]string{"FORCE_COLOR": "3"}, ProfileNoColor},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			for k, v := range tc.env {
+				t.Setenv(k, v)
+			}
+
+			if p := DetectColorProfile(nil, tc.mode); p != tc.want {
+				t.Errorf("DetectColorProfile = %v, want %v", p, tc.want)
+			}
+		})
+	}
+}
+
+func testDetectColorProfileFile(t *testing.T) {
+	f, err := os.CreateTemp(t.TempDir(), "out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if p := DetectColorProfile(f, ColorAuto); p != ProfileNoColor {
+		t.Errorf("Expected regular file to disable color, got %v", p)
+	}
+
+	t.Run("FORCE_COLOR", func(t *testing.T) {
+		t.Setenv("FORCE_COLOR", "1")
+
+		if p := DetectColorProfile(f, ColorAuto); p != ProfileANSI {
+			t.Errorf("Expected FORCE_COLOR to enable color for regular file, got %v", p)
+		}
+	})
+
+	if p := DetectColorProfile(f, ColorAlways); p != ProfileANSI {
+		t.Errorf("Expected ColorAlways to enable color for regular file, got %v", p)
+	}
+}
+
+func testNewHandlerColorMode(t *testing.T) {
+	h := NewHandler(nil, &Options{ColorMode: ColorNever})
+	if !h.opts.NoColor {
+		t.Error("Expected ColorNever to set NoColor")
+	}
+
+	h = NewHandler(nil, &Options{ColorMode: ColorAlways, NoColor: true})
+	if h.profile != ProfileNoColor {
+		t.Error("Expected explicit NoColor to win over ColorAlways")
+	}
+
+	t.Setenv("COLORTERM", "truecolor")
+	h = NewHandler(nil, nil)
+	if h.profile != ProfileTrueColor || h.opts.NoColor {
+		t.Errorf("Expected truecolor profile, got %v", h.profile)
+	}
+}
@@ -0,0 +1,266 @@
+package devslog
+
+// Theme sets the color of every visual role of the handler output.
+// A zero Color leaves the role without color.
+type Theme struct {
+	// Level badge and message colors
+	DebugLevel Color
+	InfoLevel  Color
+	WarnLevel  Color
+	ErrorLevel Color
+
+	// Text inside the level badge
+	LevelText Color
+
+	// Timestamp at the beginning of the log, always fainted
+	Timestamp Color
+
+	// Source info: "@@@" marker, file and line
+	SourceMarker Color
+	SourceFile   Color
+	SourceLine   Color
+
+	// Attribute keys
+	Key Color
+
+	// Numbers, booleans, URLs, times and durations with their markers
+	Number Color
+	Bool   Color
+	URL    Color
+	Time   Color
+
+	// Error messages, error indices, unknown types and their markers
+	Error Color
+
+	// Stack trace frames of errors
+	StackFrame Color
+
+	// Empty strings, Stringer hints and separators
+	Muted Color
+
+	// Nil values and pointer stars
+	Nil     Color
+	Pointer Color
+
+	// Type strings, brackets inside them and lengths of slices and maps
+	Type        Color
+	TypeBracket Color
+	Length      Color
+
+	// Slice indices, map keys and struct field names
+	SliceIndex  Color
+	MapKey      Color
+	StructField Color
+
+	// Markers of slices, arrays, maps and groups and the marker of structs
+	CollectionMarker Color
+	StructMarker     Color
+}
+
+// DefaultTheme returns the theme used when Options.Theme is nil.
+func DefaultTheme() *Theme {
+	return &Theme{
+		DebugLevel:       Blue,
+		InfoLevel:        Green,
+		WarnLevel:        Yellow,
+		ErrorLevel:       Red,
+		LevelText:        Black,
+		SourceMarker:     Yellow,
+		SourceFile:       Cyan,
+		SourceLine:       Red,
+		Key:              Magenta,
+		Number:           Yellow,
+		Bool:             Blue,
+		URL:              Blue,
+		Time:             Cyan,
+		Error:            Red,
+		StackFrame:       Blue,
+		Muted:            White,
+		Nil:              Yellow,
+		Pointer:          Red,
+		Type:             Yellow,
+		TypeBracket:      Green,
+		Length:           Blue,
+		SliceIndex:       Green,
+		MapKey:           Green,
+		StructField:      Green,
+		CollectionMarker: Green,
+		StructMarker:     Yellow,
+	}
+}
+
+// LightTheme returns a theme readable on terminals with a light background.
+func LightTheme() *Theme {
+	return &Theme{
+		DebugLevel:       Blue,
+		InfoLevel:        Color256(28),
+		WarnLevel:        Color256(130),
+		ErrorLevel:       Color256(124),
+		LevelText:        BrightWhite,
+		Timestamp:        Color256(240),
+		SourceMarker:     Color256(130),
+		SourceFile:       Color256(30),
+		SourceLine:       Color256(124),
+		Key:              Color256(90),
+		Number:           Color256(130),
+		Bool:             Color256(25),
+		URL:              Color256(25),
+		Time:             Color256(30),
+		Error:            Color256(124),
+		StackFrame:       Color256(25),
+		Muted:            Color256(240),
+		Nil:              Color256(130),
+		Pointer:          Color256(124),
+		Type:             Color256(94),
+		TypeBracket:      Color256(28),
+		Length:           Color256(25),
+		SliceIndex:       Color256(28),
+		MapKey:           Color256(28),
+		StructField:      Color256(28),
+		CollectionMarker: Color256(28),
+		StructMarker:     Color256(94),
+	}
+}
+
+// HighContrastTheme returns a theme using only bright colors.
+func HighContrastTheme() *Theme {
+	return &Theme{
+		DebugLevel:       BrightBlue,
+		InfoLevel:        BrightGreen,
+		WarnLevel:        BrightYellow,
+		ErrorLevel:       BrightRed,
+		LevelText:        Black,
+		Timestamp:        BrightWhite,
+		SourceMarker:     BrightYellow,
+		SourceFile:       BrightCyan,
+		SourceLine:       BrightRed,
+		Key:              BrightMagenta,
+		Number:           BrightYellow,
+		Bool:             BrightCyan,
+		URL:              BrightBlue,
+		Time:             BrightCyan,
+		Error:            BrightRed,
+		StackFrame:       BrightBlue,
+		Muted:            BrightWhite,
+		Nil:              BrightYellow,
+		Pointer:          BrightRed,
+		Type:             BrightYellow,
+		TypeBracket:      BrightGreen,
+		Length:           BrightBlue,
+		SliceIndex:       BrightGreen,
+		MapKey:           BrightGreen,
+		StructField:      BrightGreen,
+		CollectionMarker: BrightGreen,
+		StructMarker:     BrightYellow,
+	}
+}
+
+// SolarizedTheme returns a theme using the Solarized accent colors.
+func SolarizedTheme() *Theme {
+	var (
+		base01  = RGB(0x58, 0x6e, 0x75)
+		base03  = RGB(0x00, 0x2b, 0x36)
+		yellow  = RGB(0xb5, 0x89, 0x00)
+		orange  = RGB(0xcb, 0x4b, 0x16)
+		red     = RGB(0xdc, 0x32, 0x2f)
+		magenta = RGB(0xd3, 0x36, 0x82)
+		violet  = RGB(0x6c, 0x71, 0xc4)
+		blue    = RGB(0x26, 0x8b, 0xd2)
+		cyan    = RGB(0x2a, 0xa1, 0x98)
+		green   = RGB(0x85, 0x99, 0x00)
+	)
+
+	return &Theme{
+		DebugLevel:       blue,
+		InfoLevel:        green,
+		WarnLevel:        yellow,
+		ErrorLevel:       red,
+		LevelText:        base03,
+		Timestamp:        base01,
+		SourceMarker:     yellow,
+		SourceFile:       cyan,
+		SourceLine:       orange,
+		Key:              magenta,
+		Number:           orange,
+		Bool:             violet,
+		URL:              blue,
+		Time:             cyan,
+		Error:            red,
+		StackFrame:       blue,
+		Muted:            base01,
+		Nil:              yellow,
+		Pointer:          red,
+		Type:             yellow,
+		TypeBracket:      green,
+		Length:           blue,
+		SliceIndex:       green,
+		MapKey:           green,
+		StructField:      green,
+		CollectionMarker: green,
+		StructMarker:     yellow,
+	}
+}
+
+// palette holds the escape sequences of a theme resolved for the color
+// profile of the handler.
+type palette struct {
+	levelText        foregroundColor
+	timestamp        foregroundColor
+	sourceMarker     foregroundColor
+	sourceFile       foregroundColor
+	sourceLine       foregroundColor
+	key              foregroundColor
+	number           foregroundColor
+	bool             foregroundColor
+	url              foregroundColor
+	time             foregroundColor
+	err              foregroundColor
+	stackFrame       foregroundColor
+	muted            foregroundColor
+	nilValue         foregroundColor
+	pointer          foregroundColor
+	typ              foregroundColor
+	typeBracket      foregroundColor
+	length           foregroundColor
+	sliceIndex       foregroundColor
+	mapKey           foregroundColor
+	structField      foregroundColor
+	collectionMarker foregroundColor
+	structMarker     foregroundColor
+}
+
+func (h *developHandler) newPalette(t *Theme) *palette {
+	fg := func(c Color) foregroundColor {
+		if c == UnknownColor {
+			return nil
+		}
+
+		return h.getColor(c).fg
+	}
+
+	return &palette{
+		levelText:        fg(t.LevelText),
+		timestamp:        fg(t.Timestamp),
+		sourceMarker:     fg(t.SourceMarker),
+		sourceFile:       fg(t.SourceFile),
+		sourceLine:       fg(t.SourceLine),
+		key:              fg(t.Key),
+		number:           fg(t.Number),
+		bool:             fg(t.Bool),
+		url:              fg(t.URL),
+		time:             fg(t.Time),
+		err:              fg(t.Error),
+		stackFrame:       fg(t.StackFrame),
+		muted:            fg(t.Muted),
+		nilValue:         fg(t.Nil),
+		pointer:          fg(t.Pointer),
+		typ:              fg(t.Type),
+		typeBracket:      fg(t.TypeBracket),
+		length:           fg(t.Length),
+		sliceIndex:       fg(t.SliceIndex),
+		mapKey:           fg(t.MapKey),
+		structField:      fg(t.StructField),
+		collectionMarker: fg(t.CollectionMarker),
+		structMarker:     fg(t.StructMarker),
+	}
+}
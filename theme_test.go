@@ -0,0 +1,78 @@
+package devslog
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+)
+
+func TestTheme(t *testing.T) {
+	testThemeCustom(t)
+	testThemeBuiltin(t)
+	testThemeLevelColorOverride(t)
+	testThemeZeroLevelColor(t)
+}
+
+func testThemeCustom(t *testing.T) {
+	w := &MockWriter{}
+
+	theme := DefaultTheme()
+	theme.Key = Color256(208)
+	theme.Number = RGB(255, 0, 0)
+	theme.Timestamp = BrightBlack
+
+	opts := &Options{
+		TimeFormat: "[]",
+		Theme:      theme,
+		ColorMode:  ColorAlways,
+	}
+
+	h := NewHandler(w, opts)
+	h.profile = ProfileTrueColor
+	h.pal = h.newPalette(theme)
+	slog.New(h).Info("msg", slog.Int("i", 1))
+
+	expected := []byte("\x1b[2m\x1b[90m[]\x1b[0m \x1b[42m\x1b[30m INFO \x1b[0m \x1b[32mmsg\x1b[0m\n\x1b[38;2;255;0;0m#\x1b[0m \x1b[38;5;208mi\x1b[0m: \x1b[38;2;255;0;0m1\x1b[0m\n")
+
+	if !bytes.Equal(w.WrittenData, expected) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, w.WrittenData)
+	}
+}
+
+func testThemeBuiltin(t *testing.T) {
+	for _, theme := range []*Theme{DefaultTheme(), LightTheme(), HighContrastTheme(), SolarizedTheme()} {
+		for _, c := range []Color{theme.DebugLevel, theme.InfoLevel, theme.WarnLevel, theme.ErrorLevel, theme.Key, theme.Number, theme.Error} {
+			if !c.valid() {
+				t.Errorf("Expected built-in theme color %#x to be valid", uint(c))
+			}
+		}
+	}
+}
+
+func testThemeLevelColorOverride(t *testing.T) {
+	h := NewHandler(nil, &Options{Theme: SolarizedTheme(), ErrorColor: Magenta})
+
+	if h.opts.ErrorColor != Magenta {
+		t.Errorf("Expected explicit ErrorColor to win over theme")
+	}
+
+	if h.opts.InfoColor != SolarizedTheme().InfoLevel {
+		t.Errorf("Expected InfoColor to be taken from theme")
+	}
+}
+
+func testThemeZeroLevelColor(t *testing.T) {
+	w := &MockWriter{}
+
+	theme := DefaultTheme()
+	theme.InfoLevel = UnknownColor
+	theme.LevelText = UnknownColor
+
+	slog.New(NewHandler(w, &Options{TimeFormat: "[]", Theme: theme, ColorMode: ColorAlways})).Info("msg")
+
+	expected := []byte("\x1b[2m[]\x1b[0m  INFO  msg\n")
+
+	if !bytes.Equal(w.WrittenData, expected) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, w.WrittenData)
+	}
+}
@@ -0,0 +1,93 @@
+package devslog
+
+import (
+	"reflect"
+)
+
+type visitKey struct {
+	ptr uintptr
+	typ reflect.Type
+	len int // slices sharing an array are the same value only with the same length
+}
+
+// visited tracks the pointers, maps and slices on the path from the logged
+// value to the value being rendered, to detect values containing themselves,
+// and how deep the composite values are nested.
+type visited struct {
+	path  map[visitKey]struct{}
+	depth int
+}
+
+func newVisited() *visited {
+	return &visited{path: map[visitKey]struct{}{}}
+}
+
+// keyOf returns the key of v if it is a non-nil pointer, map or slice.
+func keyOf(v reflect.Value) (visitKey, bool) {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice:
+		if v.IsNil() || v.Pointer() == 0 {
+			return visitKey{}, false
+		}
+
+		k := visitKey{ptr: v.Pointer(), typ: v.Type()}
+		if v.Kind() == reflect.Slice {
+			k.len = v.Len()
+		}
+
+		return k, true
+	}
+
+	return visitKey{}, false
+}
+
+// enter adds v to the path. It returns false if v is already on the path.
+func (vi *visited) enter(v reflect.Value) bool {
+	k, ok := keyOf(v)
+	if !ok {
+		return true
+	}
+
+	if _, ok := vi.path[k]; ok {
+		return false
+	}
+
+	vi.path[k] = struct{}{}
+
+	return true
+}
+
+// leave removes v from the path.
+func (vi *visited) leave(v reflect.Value) {
+	if k, ok := keyOf(v); ok {
+		delete(vi.path, k)
+	}
+}
+
+// enterComposite starts rendering the struct, map, slice or array v of type
+// t. If v contains itself or is nested deeper than MaxDepth, it returns the
+// marker to print instead and false. Otherwise the caller must call
+// leaveComposite once v is rendered.
+func (h *developHandler) enterComposite(t reflect.Type, v reflect.Value, vi *visited) ([]byte, bool) {
+	if h.opts.MaxDepth > 0 && vi.depth >= int(h.opts.MaxDepth) {
+		return h.colorStringFainted([]byte("… "+t.String()), h.pal.muted), false
+	}
+
+	if !vi.enter(v) {
+		return h.cycleString(t), false
+	}
+
+	vi.depth++
+
+	return nil, true
+}
+
+func (h *developHandler) leaveComposite(v reflect.Value, vi *visited) {
+	vi.depth--
+	vi.leave(v)
+}
+
+// cycleString marks a value of type t containing itself.
+func (h *developHandler) cycleString(t reflect.Type) []byte {
+	return h.colorString([]byte("<cycle: "+t.String()+">"), h.pal.err)
+}
@@ -0,0 +1,209 @@
+package devslog
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+)
+
+type cycleTestNode struct {
+	Name string
+	Next *cycleTestNode
+	Tags map[string][]int
+}
+
+func TestCycles(t *testing.T) {
+	testCycleMap(t)
+	testCycleSlice(t)
+	testSubslice(t)
+	testCyclePointer(t)
+	testCycleColor(t)
+	testCycleCompact(t)
+	testSharedPointer(t)
+}
+
+func TestMaxDepth(t *testing.T) {
+	testMaxDepth(t)
+	testMaxDepthCompact(t)
+}
+
+func newCycleTestNode() *cycleTestNode {
+	n := &cycleTestNode{Name: "a", Tags: map[string][]int{"x": {1, 2}}}
+	n.Next = &cycleTestNode{Name: "b", Next: n}
+
+	return n
+}
+
+func testCycleMap(t *testing.T) {
+	w := &MockWriter{}
+	o := &Options{
+		TimeFormat: "[]",
+		NoColor:    true,
+	}
+
+	m := map[string]any{"a": 1}
+	m["self"] = m
+
+	logger := slog.New(NewHandler(w, o))
+	logger.Info("msg", slog.Any("m", m))
+
+	expected := []byte("[]  INFO  msg\nM m: 2 map[string]interface {}\n    a   : 1\n    self: <cycle: map[string]interface {}>\n")
+
+	if !bytes.Equal(w.WrittenData, expected) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, w.WrittenData)
+	}
+}
+
+func testCycleSlice(t *testing.T) {
+	w := &MockWriter{}
+	o := &Options{
+		TimeFormat: "[]",
+		NoColor:    true,
+	}
+
+	s := []any{1, nil}
+	s[1] = s
+
+	logger := slog.New(NewHandler(w, o))
+	logger.Info("msg", slog.Any("s", s))
+
+	expected := []byte("[]  INFO  msg\nS s: 2 []interface {}\n    0: 1\n    1: <cycle: []interface {}>\n")
+
+	if !bytes.Equal(w.WrittenData, expected) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, w.WrittenData)
+	}
+}
+
+func testSubslice(t *testing.T) {
+	w := &MockWriter{}
+	o := &Options{
+		TimeFormat: "[]",
+		NoColor:    true,
+	}
+
+	s := []any{nil, nil}
+	s[1] = s[:1]
+
+	logger := slog.New(NewHandler(w, o))
+	logger.Info("msg", slog.Any("s", s))
+
+	expected := []byte("[]  INFO  msg\nS s: 2 []interface {}\n    0: <nil>\n    1: 1 []interface {}\n      0: <nil>\n")
+
+	if !bytes.Equal(w.WrittenData, expected) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, w.WrittenData)
+	}
+}
+
+func testCyclePointer(t *testing.T) {
+	w := &MockWriter{}
+	o := &Options{
+		TimeFormat: "[]",
+		NoColor:    true,
+	}
+
+	logger := slog.New(NewHandler(w, o))
+	logger.Info("msg", slog.Any("n", newCycleTestNode()))
+
+	expected := []byte("[]  INFO  msg\nS n: *devslog.cycleTestNode\n    Name: a\n    Next: *devslog.cycleTestNode\n      Name: b\n      Next: <cycle: *devslog.cycleTestNode>\n      Tags: 0 map[string][]int\n    Tags: 1 map[string][]int\n      x: 2 []int\n        0: 1\n        1: 2\n")
+
+	if !bytes.Equal(w.WrittenData, expected) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, w.WrittenData)
+	}
+}
+
+func testCycleColor(t *testing.T) {
+	w := &MockWriter{}
+	o := &Options{
+		TimeFormat: "[]",
+	}
+
+	s := []any{nil}
+	s[0] = s
+
+	logger := slog.New(NewHandler(w, o))
+	logger.Info("msg", slog.Any("s", s))
+
+	expected := []byte("\x1b[2m[]\x1b[0m \x1b[42m\x1b[30m INFO \x1b[0m \x1b[32mmsg\x1b[0m\n\x1b[32mS\x1b[0m \x1b[35ms\x1b[0m: \x1b[34m1\x1b[0m \x1b[32m[\x1b[0m\x1b[32m]\x1b[0m\x1b[33mi\x1b[0m\x1b[33mn\x1b[0m\x1b[33mt\x1b[0m\x1b[33me\x1b[0m\x1b[33mr\x1b[0m\x1b[33mf\x1b[0m\x1b[33ma\x1b[0m\x1b[33mc\x1b[0m\x1b[33me\x1b[0m\x1b[33m \x1b[0m\x1b[33m{\x1b[0m\x1b[33m}\x1b[0m\n    \x1b[32m0\x1b[0m: \x1b[31m<cycle: []interface {}>\x1b[0m\n")
+
+	if !bytes.Equal(w.WrittenData, expected) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, w.WrittenData)
+	}
+}
+
+func testCycleCompact(t *testing.T) {
+	w := &MockWriter{}
+	o := &Options{
+		TimeFormat: "[]",
+		NoColor:    true,
+		Compact:    true,
+	}
+
+	m := map[string]any{"a": 1}
+	m["self"] = m
+
+	logger := slog.New(NewHandler(w, o))
+	logger.Info("msg", slog.Any("m", m), slog.Any("n", newCycleTestNode()))
+
+	expected := []byte("[]  INFO  msg m={a: 1, self: <cycle: map[string]interface {}>} n={Name: a, Next: {Name: b, Next: <cycle: *devslog.cycleTestNode>, Tags: {}}, Tags: {x: [1, 2]}}\n")
+
+	if !bytes.Equal(w.WrittenData, expected) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, w.WrittenData)
+	}
+}
+
+func testSharedPointer(t *testing.T) {
+	w := &MockWriter{}
+	o := &Options{
+		TimeFormat: "[]",
+		NoColor:    true,
+		Compact:    true,
+	}
+
+	shared := &cycleTestNode{Name: "shared"}
+
+	logger := slog.New(NewHandler(w, o))
+	logger.Info("msg", slog.Any("s", []*cycleTestNode{shared, shared}))
+
+	expected := []byte("[]  INFO  msg s=[{Name: shared, Next: <nil>, Tags: {}}, {Name: shared, Next: <nil>, Tags: {}}]\n")
+
+	if !bytes.Equal(w.WrittenData, expected) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, w.WrittenData)
+	}
+}
+
+func testMaxDepth(t *testing.T) {
+	w := &MockWriter{}
+	o := &Options{
+		TimeFormat: "[]",
+		NoColor:    true,
+		MaxDepth:   2,
+	}
+
+	logger := slog.New(NewHandler(w, o))
+	logger.Info("msg", slog.Any("n", newCycleTestNode()), slog.Any("s", [][]int{{1}}))
+
+	expected := []byte("[]  INFO  msg\nS n: *devslog.cycleTestNode\n    Name: a\n    Next: *devslog.cycleTestNode\n      Name: b\n      Next: <cycle: *devslog.cycleTestNode>\n      Tags: … map[string][]int\n    Tags: 1 map[string][]int\n      x: … []int\nS s: 1 [][]int\n    0: 1 []int\n      0: 1\n")
+
+	if !bytes.Equal(w.WrittenData, expected) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, w.WrittenData)
+	}
+}
+
+func testMaxDepthCompact(t *testing.T) {
+	w := &MockWriter{}
+	o := &Options{
+		TimeFormat: "[]",
+		NoColor:    true,
+		Compact:    true,
+		MaxDepth:   1,
+	}
+
+	logger := slog.New(NewHandler(w, o))
+	logger.Info("msg", slog.Any("s", [][]int{{1}, {2}}))
+
+	expected := []byte("[]  INFO  msg s=[… []int, … []int]\n")
+
+	if !bytes.Equal(w.WrittenData, expected) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, w.WrittenData)
+	}
+}
@@ -0,0 +1,273 @@
+package devslog
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// visibleWidth returns the number of columns b takes on the terminal,
+// ignoring ANSI escape sequences.
+func visibleWidth(b []byte) (w int) {
+	var wc widthCounter
+	for len(b) > 0 {
+		if b[0] == '\x1b' {
+			b = skipEscape(b)
+			continue
+		}
+
+		r, size := utf8.DecodeRune(b)
+		b = b[size:]
+		w += wc.next(r)
+	}
+
+	return w
+}
+
+// stringWidth returns the number of columns s takes on the terminal.
+func stringWidth(s string) (w int) {
+	var wc widthCounter
+	for _, r := range s {
+		w += wc.next(r)
+	}
+
+	return w
+}
+
+// widthCounter measures the display width of a sequence of runes. Runes
+// joined by a zero width joiner and pairs of regional indicators (flags)
+// are rendered as a single wide glyph.
+type widthCounter struct {
+	joined bool
+	flag   bool
+}
+
+// next returns the number of columns r adds to the runes before it.
+func (wc *widthCounter) next(r rune) int {
+	if wc.joined {
+		wc.joined = false
+		return 0
+	}
+
+	if r == '\u200d' {
+		wc.joined = true
+		return 0
+	}
+
+	if r >= 0x1f1e6 && r <= 0x1f1ff {
+		wc.flag = !wc.flag
+		if !wc.flag {
+			return 0
+		}
+
+		return 2
+	}
+
+	wc.flag = false
+
+	return runeWidth(r)
+}
+
+// runeWidth returns the number of columns r takes on the terminal: 0 for
+// control characters, combining marks and format characters, 2 for East
+// Asian wide and fullwidth characters and emoji, otherwise 1.
+func runeWidth(r rune) int {
+	switch {
+	case r < 0x20, r >= 0x7f && r < 0xa0:
+		return 0
+	case r < 0x300:
+		return 1
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		return 0
+	case r >= 0x1160 && r <= 0x11ff:
+		// Hangul Jamo medial vowels and final consonants combine with the initial consonant
+		return 0
+	}
+
+	i := sort.Search(len(wideRanges), func(i int) bool {
+		return wideRanges[i].hi >= r
+	})
+
+	if i < len(wideRanges) && wideRanges[i].lo <= r {
+		return 2
+	}
+
+	return 1
+}
+
+// wideRanges are the East Asian Wide (W) and Fullwidth (F) ranges of Unicode
+// 15, including emoji with default emoji presentation, sorted by code point.
+var wideRanges = []struct{ lo, hi rune }{
+	{0x1100, 0x115f},
+	{0x231a, 0x231b},
+	{0x2329, 0x232a},
+	{0x23e9, 0x23ec},
+	{0x23f0, 0x23f0},
+	{0x23f3, 0x23f3},
+	{0x25fd, 0x25fe},
+	{0x2614, 0x2615},
+	{0x2648, 0x2653},
+	{0x267f, 0x267f},
+	{0x2693, 0x2693},
+	{0x26a1, 0x26a1},
+	{0x26aa, 0x26ab},
+	{0x26bd, 0x26be},
+	{0x26c4, 0x26c5},
+	{0x26ce, 0x26ce},
+	{0x26d4, 0x26d4},
+	{0x26ea, 0x26ea},
+	{0x26f2, 0x26f3},
+	{0x26f5, 0x26f5},
+	{0x26fa, 0x26fa},
+	{0x26fd, 0x26fd},
+	{0x2705, 0x2705},
+	{0x270a, 0x270b},
+	{0x2728, 0x2728},
+	{0x274c, 0x274c},
+	{0x274e, 0x274e},
+	{0x2753, 0x2755},
+	{0x2757, 0x2757},
+	{0x2795, 0x2797},
+	{0x27b0, 0x27b0},
+	{0x27bf, 0x27bf},
+	{0x2b1b, 0x2b1c},
+	{0x2b50, 0x2b50},
+	{0x2b55, 0x2b55},
+	{0x2e80, 0x303e},
+	{0x3041, 0x33ff},
+	{0x3400, 0x4dbf},
+	{0x4e00, 0x9fff},
+	{0xa000, 0xa4cf},
+	{0xa960, 0xa97f},
+	{0xac00, 0xd7a3},
+	{0xf900, 0xfaff},
+	{0xfe10, 0xfe19},
+	{0xfe30, 0xfe6f},
+	{0xff00, 0xff60},
+	{0xffe0, 0xffe6},
+	{0x16fe0, 0x16fe4},
+	{0x17000, 0x18aff},
+	{0x1b000, 0x1b2ff},
+	{0x1f004, 0x1f004},
+	{0x1f0cf, 0x1f0cf},
+	{0x1f18e, 0x1f18e},
+	{0x1f191, 0x1f19a},
+	{0x1f200, 0x1f251},
+	{0x1f260, 0x1f265},
+	{0x1f300, 0x1f64f},
+	{0x1f680, 0x1f6ff},
+	{0x1f7e0, 0x1f7eb},
+	{0x1f90c, 0x1f9ff},
+	{0x1fa70, 0x1faff},
+	{0x20000, 0x2fffd},
+	{0x30000, 0x3fffd},
+}
+
+// skipEscape drops the escape sequence at the beginning of b, either a CSI
+// sequence (colors) or an OSC sequence (hyperlinks).
+func skipEscape(b []byte) []byte {
+	if len(b) >= 2 && b[1] == ']' {
+		for i := 2; i < len(b); i++ {
+			switch {
+			case b[i] == '\a':
+				return b[i+1:]
+			case b[i] == '\x1b' && i+1 < len(b) && b[i+1] == '\\':
+				return b[i+2:]
+			}
+		}
+
+		return nil
+	}
+
+	if len(b) < 2 || b[1] != '[' {
+		return b[1:]
+	}
+
+	for i := 2; i < len(b); i++ {
+		if b[i] >= 0x40 && b[i] <= 0x7e {
+			return b[i+1:]
+		}
+	}
+
+	return nil
+}
+
+// Values are not wrapped into less than this number of columns.
+const minWrapWidth = 20
+
+// indentText soft-wraps s at the terminal width with Wrap and indents the
+// continuation lines to column col. Embedded newlines are indented as well
+// when indentNewlines is set.
+func (h *developHandler) indentText(s string, col int, indentNewlines bool) string {
+	wrap := h.opts.Wrap && h.width-col >= minWrapWidth
+	if !wrap && !indentNewlines {
+		return s
+	}
+
+	if !wrap {
+		return strings.ReplaceAll(s, "\n", "\n"+strings.Repeat(" ", col))
+	}
+
+	indent := "\n" + strings.Repeat(" ", col)
+
+	var b strings.Builder
+	for i, line := range strings.Split(s, "\n") {
+		first := h.width - col
+		if i > 0 {
+			if indentNewlines {
+				b.WriteString(indent)
+			} else {
+				b.WriteByte('\n')
+				first = h.width
+			}
+		}
+
+		for j, chunk := range wrapLine(line, first, h.width-col) {
+			if j > 0 {
+				b.WriteString(indent)
+			}
+
+			b.WriteString(chunk)
+		}
+	}
+
+	return b.String()
+}
+
+// wrapLine splits s into chunks fitting into first columns for the first
+// chunk and rest columns for the others, preferably at spaces.
+func wrapLine(s string, first, rest int) (chunks []string) {
+	avail := first
+	for {
+		var wc widthCounter
+		cut, space, w := -1, -1, 0
+		for i, r := range s {
+			if r == ' ' {
+				space = i
+			}
+
+			rw := wc.next(r)
+			if w+rw > avail && i > 0 {
+				cut = i
+				break
+			}
+
+			w += rw
+		}
+
+		if cut < 0 {
+			return append(chunks, s)
+		}
+
+		if space > 0 {
+			chunks = append(chunks, s[:space])
+			s = s[space+1:]
+		} else {
+			chunks = append(chunks, s[:cut])
+			s = s[cut:]
+		}
+
+		avail = rest
+	}
+}
@@ -0,0 +1,214 @@
+package devslog
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"testing"
+)
+
+func TestWrap(t *testing.T) {
+	testWrapString(t)
+	testWrapHashColor(t)
+	testWrapStruct(t)
+	testWrapError(t)
+	testWrapDisabled(t)
+	testWrapLine(t)
+	testDetectWidth(t)
+}
+
+func testWrapString(t *testing.T) {
+	w := &MockWriter{}
+	opts := &Options{
+		TimeFormat: "[]",
+		NoColor:    true,
+		Wrap:       true,
+		Width:      30,
+	}
+
+	logger := slog.New(NewHandler(w, opts))
+	logger.Info("msg", slog.String("text", "the quick brown fox jumps over the lazy dog"))
+
+	expected := []byte("[]  INFO  msg\n  text: the quick brown fox\n        jumps over the lazy\n        dog\n")
+
+	if !bytes.Equal(w.WrittenData, expected) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, w.WrittenData)
+	}
+}
+
+func testWrapHashColor(t *testing.T) {
+	w := &MockWriter{}
+	opts := &Options{
+		TimeFormat:    "[]",
+		Wrap:          true,
+		Width:         30,
+		HashColorKeys: []string{"id"},
+	}
+
+	logger := slog.New(NewHandler(w, opts))
+	logger.Info("msg", slog.String("id", "the quick brown fox jumps over the lazy dog"))
+
+	h := NewHandler(nil, opts)
+	c := h.getColor(h.hashColor("the quick brown fox jumps over the lazy dog")).fg
+	value := h.colorString([]byte("the quick brown fox\n      jumps over the lazy dog"), c)
+	expected := []byte("\x1b[2m[]\x1b[0m \x1b[42m\x1b[30m INFO \x1b[0m \x1b[32mmsg\x1b[0m\n  \x1b[35mid\x1b[0m: " + string(value) + "\n")
+
+	if !bytes.Equal(w.WrittenData, expected) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, w.WrittenData)
+	}
+}
+
+func testWrapStruct(t *testing.T) {
+	w := &MockWriter{}
+	opts := &Options{
+		TimeFormat: "[]",
+		NoColor:    true,
+		Wrap:       true,
+		Width:      30,
+	}
+
+	type note struct {
+		Body string
+	}
+
+	logger := slog.New(NewHandler(w, opts))
+	logger.Info("msg", slog.Any("n", note{Body: "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"}))
+
+	expected := []byte("[]  INFO  msg\nS n: devslog.note\n    Body: aaaaaaaaaaaaaaaaaaaa\n          aaaaaaaaaa\n")
+
+	if !bytes.Equal(w.WrittenData, expected) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, w.WrittenData)
+	}
+}
+
+func testWrapError(t *testing.T) {
+	w := &MockWriter{}
+	opts := &Options{
+		TimeFormat: "[]",
+		NoColor:    true,
+		Wrap:       true,
+		Width:      30,
+	}
+
+	logger := slog.New(NewHandler(w, opts))
+	logger.Info("msg", slog.Any("e", errors.New("connection refused by the remote host")))
+
+	expected := []byte("[]  INFO  msg\nE e: \n    0: connection refused by\n       the remote host\n")
+
+	if !bytes.Equal(w.WrittenData, expected) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, w.WrittenData)
+	}
+}
+
+func testWrapDisabled(t *testing.T) {
+	w := &MockWriter{}
+	opts := &Options{
+		TimeFormat: "[]",
+		NoColor:    true,
+		Width:      30,
+	}
+
+	logger := slog.New(NewHandler(w, opts))
+	logger.Info("msg", slog.String("text", "the quick brown fox jumps over the lazy dog"))
+
+	expected := []byte("[]  INFO  msg\n  text: the quick brown fox jumps over the lazy dog\n")
+
+	if !bytes.Equal(w.WrittenData, expected) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, w.WrittenData)
+	}
+}
+
+func testWrapLine(t *testing.T) {
+	cases := []struct {
+		in          string
+		first, rest int
+		expected    []string
+	}{
+		{"short", 10, 10, []string{"short"}},
+		{"aaaa bbbb cccc", 9, 9, []string{"aaaa bbbb", "cccc"}},
+		{"aaaaaaaaaaaa", 5, 4, []string{"aaaaa", "aaaa", "aaa"}},
+		{"aa bbbbbbbbbb", 5, 5, []string{"aa", "bbbbb", "bbbbb"}},
+	}
+
+	for _, c := range cases {
+		got := wrapLine(c.in, c.first, c.rest)
+		if len(got) != len(c.expected) {
+			t.Errorf("wrapLine(%q, %d, %d) = %q, want %q", c.in, c.first, c.rest, got, c.expected)
+			continue
+		}
+
+		for i := range got {
+			if got[i] != c.expected[i] {
+				t.Errorf("wrapLine(%q, %d, %d) = %q, want %q", c.in, c.first, c.rest, got, c.expected)
+				break
+			}
+		}
+	}
+}
+
+func testDetectWidth(t *testing.T) {
+	t.Setenv("COLUMNS", "123")
+	if width := DetectWidth(&MockWriter{}); width != 123 {
+		t.Errorf("DetectWidth with COLUMNS=123 = %d, want 123", width)
+	}
+
+	t.Setenv("COLUMNS", "wide")
+	if width := DetectWidth(&MockWriter{}); width != 0 {
+		t.Errorf("DetectWidth with COLUMNS=wide = %d, want 0", width)
+	}
+}
+
+func TestDisplayWidth(t *testing.T) {
+	testStringWidth(t)
+	testWideKeyAlignment(t)
+}
+
+func testStringWidth(t *testing.T) {
+	cases := map[string]int{
+		"":            0,
+		"abc":         3,
+		"naïve":       5,
+		"nai\u0308ve": 5,
+		"日本語":         6,
+		"ｈｉ":          4,
+		"한국":          4,
+		"🙂":           2,
+		"\U0001f468\u200d\U0001f469\u200d\U0001f467": 2,
+		"🇨🇿":           2,
+		"\u2764\ufe0f": 1,
+		"a\u200bb":     2,
+	}
+
+	for in, expected := range cases {
+		if w := stringWidth(in); w != expected {
+			t.Errorf("stringWidth(%q) = %d, want %d", in, w, expected)
+		}
+	}
+}
+
+func testWideKeyAlignment(t *testing.T) {
+	w := &MockWriter{}
+	opts := &Options{
+		TimeFormat: "[]",
+		NoColor:    true,
+	}
+
+	type user struct {
+		Name  string
+		Größe int
+	}
+
+	logger := slog.New(NewHandler(w, opts))
+	logger.Info("msg",
+		slog.String("名前", "a"),
+		slog.String("id", "b"),
+		slog.Any("m", map[string]int{"東京": 1, "x": 2}),
+		slog.Any("u", user{Name: "c", Größe: 3}),
+	)
+
+	expected := []byte("[]  INFO  msg\n  名前: a\n  id  : b\nM m   : 2 map[string]int\n    x   : 2\n    東京: 1\nS u   : devslog.user\n    Name : c\n    Größe: 3\n")
+
+	if !bytes.Equal(w.WrittenData, expected) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, w.WrittenData)
+	}
+}
@@ -0,0 +1,101 @@
+package devslog
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+)
+
+// WrapErr returns an error wrapping err with attributes describing it, e.g.
+// the ID of the entity which failed to load. The handler prints them below
+// the message of err in the error chain. The message of the returned error
+// is the message of err. WrapErr returns nil if err is nil.
+//
+// Other handlers see the error through its MarshalJSON method, which
+// slog.JSONHandler uses to log the message together with the attributes of
+// the whole chain.
+func WrapErr(err error, attrs ...slog.Attr) error {
+	if err == nil {
+		return nil
+	}
+
+	return &attrError{err: err, attrs: attrs}
+}
+
+type attrError struct {
+	err   error
+	attrs []slog.Attr
+}
+
+func (e *attrError) Error() string {
+	return e.err.Error()
+}
+
+func (e *attrError) Unwrap() error {
+	return e.err
+}
+
+// MarshalJSON returns an object with the message of the error under "msg"
+// and the attributes of all errors wrapped with WrapErr in the chain. The
+// outermost attribute wins when keys collide.
+func (e *attrError) MarshalJSON() ([]byte, error) {
+	m := map[string]any{"msg": e.Error()}
+	for err := error(e); err != nil; err = errors.Unwrap(err) {
+		ae, ok := err.(*attrError)
+		if !ok {
+			continue
+		}
+
+		for _, a := range ae.attrs {
+			if _, ok := m[a.Key]; !ok && a.Key != "" {
+				m[a.Key] = jsonValue(a.Value)
+			}
+		}
+	}
+
+	return json.Marshal(m)
+}
+
+// jsonValue converts v to a value encoding/json marshals the same way as
+// slog.JSONHandler.
+func jsonValue(v slog.Value) any {
+	v = v.Resolve()
+	switch v.Kind() {
+	case slog.KindGroup:
+		m := map[string]any{}
+		for _, a := range v.Group() {
+			m[a.Key] = jsonValue(a.Value)
+		}
+
+		return m
+	case slog.KindDuration:
+		return int64(v.Duration())
+	case slog.KindAny:
+		if err, ok := v.Any().(error); ok {
+			if _, ok := err.(json.Marshaler); !ok {
+				return err.Error()
+			}
+		}
+	}
+
+	return v.Any()
+}
+
+// unwrapAttrs skips the errors wrapped with WrapErr at the beginning of the
+// chain and returns the first other error with their attributes.
+func unwrapAttrs(err error) (error, []slog.Attr) {
+	var attrs []slog.Attr
+	for {
+		ae, ok := err.(*attrError)
+		if !ok {
+			return err, attrs
+		}
+
+		for _, a := range ae.attrs {
+			a.Value = a.Value.Resolve()
+			attrs = append(attrs, a)
+		}
+
+		err = ae.err
+	}
+}
@@ -0,0 +1,70 @@
+package devslog
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestWrapErr(t *testing.T) {
+	testWrapErr(t)
+	testWrapErrJSON(t)
+	testWrapErrNil(t)
+}
+
+func testWrapErr(t *testing.T) {
+	w := &MockWriter{}
+	o := &Options{
+		TimeFormat: "[]",
+		NoColor:    true,
+	}
+
+	err := WrapErr(io.EOF, slog.Int("offset", 42))
+	err = WrapErr(fmt.Errorf("read config: %w", err), slog.String("path", "app.yaml"), slog.Bool("retry", false))
+
+	logger := slog.New(NewHandler(w, o))
+	logger.Info("msg", slog.Any("e", err))
+
+	expected := []byte("[]  INFO  msg\nE e: \n    0: read config\n          path : app.yaml\n        # retry: false\n    1: EOF\n        # offset: 42\n")
+
+	if !bytes.Equal(w.WrittenData, expected) {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s\nExpected:\n%[1]q\nGot:\n%[2]q", expected, w.WrittenData)
+	}
+
+	if !errors.Is(err, io.EOF) || err.Error() != "read config: EOF" {
+		t.Errorf("WrapErr changed the error: %v", err)
+	}
+}
+
+func testWrapErrJSON(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey && len(groups) == 0 {
+				return slog.Attr{}
+			}
+
+			return a
+		},
+	}))
+
+	err := WrapErr(io.EOF, slog.Int("offset", 42), slog.String("path", "inner"))
+	err = WrapErr(fmt.Errorf("read: %w", err), slog.String("path", "outer"), slog.Duration("took", time.Second))
+	logger.Info("msg", slog.Any("err", err))
+
+	expected := `{"level":"INFO","msg":"msg","err":{"msg":"read: EOF","offset":42,"path":"outer","took":1000000000}}` + "\n"
+
+	if buf.String() != expected {
+		t.Errorf("\nExpected:\n%s\nGot:\n%s", expected, buf.String())
+	}
+}
+
+func testWrapErrNil(t *testing.T) {
+	if err := WrapErr(nil, slog.Int("i", 1)); err != nil {
+		t.Errorf("WrapErr(nil) = %v, want nil", err)
+	}
+}